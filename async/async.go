@@ -1,6 +1,12 @@
 package async
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidInterval = errors.New("poll interval must be positive")
 
 // IsCancelled returns the cancelled status of the context at the current moment of time (non-blocking)
 func IsCancelled(ctx context.Context) bool {
@@ -11,3 +17,84 @@ func IsCancelled(ctx context.Context) bool {
 		return false
 	}
 }
+
+// mergedContext is cancelled when either parent is cancelled, and looks up values in both parents
+type mergedContext struct {
+	context.Context
+	other context.Context
+}
+
+// Deadline returns the earliest deadline of the two parents
+func (c *mergedContext) Deadline() (time.Time, bool) {
+	deadline, ok := c.Context.Deadline()
+	otherDeadline, otherOk := c.other.Deadline()
+	if !ok || (otherOk && otherDeadline.Before(deadline)) {
+		return otherDeadline, otherOk
+	}
+	return deadline, ok
+}
+
+// Value returns the value for the key from the first parent, falling back to the second one
+func (c *mergedContext) Value(key any) any {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+	return c.other.Value(key)
+}
+
+// Merge returns a context that is cancelled when either parent is cancelled (with that parent's cause),
+// and carries the values of both (the first parent takes precedence).
+// The cancel function must be called to release resources, as with context.WithCancel.
+func Merge(first, second context.Context) (context.Context, context.CancelFunc) {
+	// Derive the cancellation from the first parent
+	ctx, cancel := context.WithCancelCause(first)
+
+	// Propagate the cancellation of the second parent
+	stop := context.AfterFunc(second, func() {
+		cancel(context.Cause(second))
+	})
+
+	// Return the merged context
+	return &mergedContext{Context: ctx, other: second}, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// Detach returns a context that keeps the values of the parent but is never cancelled with it (i.e. cleanup after shutdown)
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// DetachWithTimeout returns a detached context (see Detach) that is cancelled after the timeout
+func DetachWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), timeout)
+}
+
+// PollUntil calls cond immediately and then every interval, until it returns true, an error, or the context is cancelled.
+// A non-positive interval returns ErrInvalidInterval without calling cond.
+func PollUntil(ctx context.Context, interval time.Duration, cond func(context.Context) (bool, error)) error {
+	// Validate the interval
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	// Create the ticker (started before the first check, so slow conditions do not drift the interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Check the condition
+		done, err := cond(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		// Wait for the next tick or the cancellation of the context
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -2,6 +2,7 @@ package async
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -32,3 +33,142 @@ func TestIsCancelled(t *testing.T) {
 		assert.True(t, IsCancelled(ctx))
 	})
 }
+
+type ctxKey string
+
+func TestMerge(t *testing.T) {
+	t.Run("Cancelled by first parent", func(t *testing.T) {
+		first, cancelFirst := context.WithCancel(context.Background())
+		ctx, cancel := Merge(first, context.Background())
+		defer cancel()
+
+		assert.False(t, IsCancelled(ctx))
+		cancelFirst()
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("Cancelled by second parent with its cause", func(t *testing.T) {
+		errShutdown := errors.New("shutdown")
+		second, cancelSecond := context.WithCancelCause(context.Background())
+		ctx, cancel := Merge(context.Background(), second)
+		defer cancel()
+
+		cancelSecond(errShutdown)
+		<-ctx.Done()
+		assert.ErrorIs(t, context.Cause(ctx), errShutdown)
+	})
+
+	t.Run("Cancel function cancels the merged context only", func(t *testing.T) {
+		first, second := context.Background(), context.Background()
+		ctx, cancel := Merge(first, second)
+
+		cancel()
+		assert.True(t, IsCancelled(ctx))
+		assert.False(t, IsCancelled(first))
+		assert.False(t, IsCancelled(second))
+	})
+
+	t.Run("Values from both parents", func(t *testing.T) {
+		first := context.WithValue(context.Background(), ctxKey("a"), "first-a")
+		second := context.WithValue(context.WithValue(context.Background(), ctxKey("a"), "second-a"), ctxKey("b"), "second-b")
+		ctx, cancel := Merge(first, second)
+		defer cancel()
+
+		assert.Equal(t, "first-a", ctx.Value(ctxKey("a")))
+		assert.Equal(t, "second-b", ctx.Value(ctxKey("b")))
+		assert.Nil(t, ctx.Value(ctxKey("c")))
+	})
+
+	t.Run("Earliest deadline", func(t *testing.T) {
+		soon := time.Now().Add(time.Minute)
+		first, cancelFirst := context.WithDeadline(context.Background(), time.Now().Add(time.Hour))
+		defer cancelFirst()
+		second, cancelSecond := context.WithDeadline(context.Background(), soon)
+		defer cancelSecond()
+
+		ctx, cancel := Merge(first, second)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, soon, deadline)
+	})
+
+	t.Run("Children are cancelled with the merged context", func(t *testing.T) {
+		second, cancelSecond := context.WithCancel(context.Background())
+		ctx, cancel := Merge(context.Background(), second)
+		defer cancel()
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+
+		cancelSecond()
+		select {
+		case <-child.Done():
+		case <-time.After(time.Second):
+			t.Fatal("child context was not cancelled")
+		}
+	})
+}
+
+func TestDetach(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey("id"), 42))
+	detached := Detach(parent)
+	cancel()
+
+	assert.True(t, IsCancelled(parent))
+	assert.False(t, IsCancelled(detached))
+	assert.Equal(t, 42, detached.Value(ctxKey("id")))
+
+	withTimeout, cancelTimeout := DetachWithTimeout(parent, 10*time.Millisecond)
+	defer cancelTimeout()
+	assert.False(t, IsCancelled(withTimeout))
+	<-withTimeout.Done()
+	assert.ErrorIs(t, withTimeout.Err(), context.DeadlineExceeded)
+}
+
+func TestPollUntil(t *testing.T) {
+	t.Run("Stops when condition is met", func(t *testing.T) {
+		calls := 0
+		err := PollUntil(context.Background(), time.Millisecond, func(ctx context.Context) (bool, error) {
+			calls++
+			return calls == 3, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Stops on error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		err := PollUntil(context.Background(), time.Millisecond, func(ctx context.Context) (bool, error) {
+			return false, errBoom
+		})
+
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("Stops on context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := PollUntil(ctx, 5*time.Millisecond, func(ctx context.Context) (bool, error) {
+			return false, nil
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Rejects non-positive intervals", func(t *testing.T) {
+		for _, interval := range []time.Duration{0, -time.Second} {
+			calls := 0
+			err := PollUntil(context.Background(), interval, func(ctx context.Context) (bool, error) {
+				calls++
+				return true, nil
+			})
+
+			assert.ErrorIs(t, err, ErrInvalidInterval)
+			assert.Zero(t, calls)
+		}
+	})
+}
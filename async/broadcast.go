@@ -0,0 +1,251 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultSubscriberBuffer = 16
+)
+
+var (
+	ErrBroadcasterClosed = errors.New("broadcaster is closed")
+)
+
+// SlowPolicy decides what happens when a subscriber falls behind the publisher
+type SlowPolicy byte
+
+const (
+	Block      SlowPolicy = iota // The publisher waits until the subscriber has room
+	DropOldest                   // The oldest buffered event is discarded to make room
+	DropNewest                   // The new event is discarded
+	Disconnect                   // The subscriber is unsubscribed
+)
+
+// SubscribeOptions configures a subscription
+type SubscribeOptions struct {
+	Context context.Context
+	Buffer  int
+	Policy  SlowPolicy
+}
+
+// Broadcaster fans out published events to all subscribers, each with its own channel
+type Broadcaster[T any] struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+}
+
+// NewBroadcaster creates a new broadcaster
+func NewBroadcaster[T any]() *Broadcaster[T] {
+	return &Broadcaster[T]{
+		subs: make(map[*Subscription[T]]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber (a non-positive buffer defaults to 16).
+// The subscription ends when the context is cancelled, Unsubscribe is called, or the broadcaster is closed.
+func (b *Broadcaster[T]) Subscribe(opts SubscribeOptions) *Subscription[T] {
+	// Use the default buffer if none is provided
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = defaultSubscriberBuffer
+	}
+
+	// Create the subscription
+	s := &Subscription[T]{
+		broadcaster: b,
+		ch:          make(chan T, buffer),
+		done:        make(chan struct{}),
+		policy:      opts.Policy,
+	}
+
+	// Register the subscription (closed broadcasters return an ended subscription)
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		s.Unsubscribe()
+		return s
+	}
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+
+	// End the subscription when the context is cancelled
+	if opts.Context != nil {
+		s.mu.Lock()
+		s.stopCtx = context.AfterFunc(opts.Context, s.Unsubscribe)
+		s.mu.Unlock()
+	}
+
+	// Return the subscription
+	return s
+}
+
+// Publish delivers the event to all subscribers according to their policies.
+// Only subscribers with the Block policy can make it wait, until the context is cancelled.
+func (b *Broadcaster[T]) Publish(ctx context.Context, event T) error {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Snapshot the subscribers
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBroadcasterClosed
+	}
+	subs := make([]*Subscription[T], 0, len(b.subs))
+	for s := range b.subs {
+		subs = append(subs, s)
+	}
+	b.mu.RUnlock()
+
+	// Deliver the event to each subscriber
+	for _, s := range subs {
+		delivered, err := s.deliver(ctx, event)
+		if err != nil {
+			return err
+		}
+		if !delivered && s.policy == Disconnect {
+			s.Unsubscribe()
+		}
+	}
+
+	// Return nil (success)
+	return nil
+}
+
+// Len returns the number of active subscribers
+func (b *Broadcaster[T]) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close ends all subscriptions and rejects further events
+func (b *Broadcaster[T]) Close() {
+	// Mark the broadcaster closed and take the subscribers
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[*Subscription[T]]struct{})
+	b.mu.Unlock()
+
+	// End all subscriptions
+	for s := range subs {
+		s.Unsubscribe()
+	}
+}
+
+// remove unregisters the subscription
+func (b *Broadcaster[T]) remove(s *Subscription[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, s)
+}
+
+// Subscription receives the events of a Broadcaster on its own channel
+type Subscription[T any] struct {
+	broadcaster *Broadcaster[T]
+	ch          chan T
+	done        chan struct{}
+	policy      SlowPolicy
+	dropped     atomic.Uint64
+	mu          sync.RWMutex // Held for reading while sending, so the channel is closed only when no send is in progress
+	once        sync.Once
+	stopCtx     func() bool
+}
+
+// C returns the channel of events (closed when the subscription ends)
+func (s *Subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+// Done returns a channel that is closed when the subscription ends
+func (s *Subscription[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Dropped returns the number of events discarded because the subscriber was too slow
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe ends the subscription and closes its channel (safe to call multiple times)
+func (s *Subscription[T]) Unsubscribe() {
+	s.once.Do(func() {
+		// Release any publisher blocked on this subscriber
+		close(s.done)
+
+		// Unregister the subscription
+		s.broadcaster.remove(s)
+
+		// Wait until no send is in progress
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// Release the context watcher
+		if s.stopCtx != nil {
+			s.stopCtx()
+		}
+
+		// Close the channel
+		close(s.ch)
+	})
+}
+
+// deliver sends the event according to the policy, returning false if it could not be delivered
+func (s *Subscription[T]) deliver(ctx context.Context, event T) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Skip ended subscriptions
+	select {
+	case <-s.done:
+		return false, nil
+	default:
+	}
+
+	// Try to send without blocking
+	select {
+	case s.ch <- event:
+		return true, nil
+	default:
+	}
+
+	// The subscriber is behind, apply the policy
+	switch s.policy {
+	case Block:
+		select {
+		case s.ch <- event:
+			return true, nil
+		case <-s.done:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	case DropOldest:
+		for {
+			// Discard the oldest event
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+			// Retry the send
+			select {
+			case s.ch <- event:
+				return true, nil
+			default:
+			}
+		}
+	}
+
+	// Drop the new event (DropNewest and Disconnect)
+	s.dropped.Add(1)
+	return false, nil
+}
@@ -0,0 +1,186 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drain reads all currently buffered events from the subscription
+func drain[T any](s *Subscription[T]) []T {
+	var events []T
+	for {
+		select {
+		case event, ok := <-s.C():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	t.Run("Fans out to all subscribers", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		s1 := b.Subscribe(SubscribeOptions{})
+		s2 := b.Subscribe(SubscribeOptions{})
+
+		for i := range 3 {
+			assert.NoError(t, b.Publish(context.Background(), i))
+		}
+
+		assert.Equal(t, []int{0, 1, 2}, drain(s1))
+		assert.Equal(t, []int{0, 1, 2}, drain(s2))
+		assert.Equal(t, 2, b.Len())
+	})
+
+	t.Run("Drop newest keeps buffered events", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		s := b.Subscribe(SubscribeOptions{Buffer: 2, Policy: DropNewest})
+
+		for i := range 5 {
+			assert.NoError(t, b.Publish(context.Background(), i))
+		}
+
+		assert.Equal(t, []int{0, 1}, drain(s))
+		assert.Equal(t, uint64(3), s.Dropped())
+	})
+
+	t.Run("Drop oldest keeps latest events", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		s := b.Subscribe(SubscribeOptions{Buffer: 2, Policy: DropOldest})
+
+		for i := range 5 {
+			assert.NoError(t, b.Publish(context.Background(), i))
+		}
+
+		assert.Equal(t, []int{3, 4}, drain(s))
+		assert.Equal(t, uint64(3), s.Dropped())
+	})
+
+	t.Run("Disconnect removes slow subscriber", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		slow := b.Subscribe(SubscribeOptions{Buffer: 1, Policy: Disconnect})
+		fast := b.Subscribe(SubscribeOptions{Buffer: 10})
+
+		for i := range 3 {
+			assert.NoError(t, b.Publish(context.Background(), i))
+		}
+
+		<-slow.Done()
+		assert.Equal(t, []int{0}, drain(slow))
+		assert.Equal(t, []int{0, 1, 2}, drain(fast))
+		assert.Equal(t, 1, b.Len())
+	})
+
+	t.Run("Block waits for the subscriber", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		s := b.Subscribe(SubscribeOptions{Buffer: 1, Policy: Block})
+
+		assert.NoError(t, b.Publish(context.Background(), 1))
+
+		published := Go(context.Background(), func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, b.Publish(ctx, 2)
+		})
+		time.Sleep(10 * time.Millisecond)
+		assert.False(t, published.IsDone())
+
+		assert.Equal(t, 1, <-s.C())
+		_, err := published.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, <-s.C())
+	})
+
+	t.Run("Block respects publisher context", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		_ = b.Subscribe(SubscribeOptions{Buffer: 1, Policy: Block})
+		assert.NoError(t, b.Publish(context.Background(), 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, b.Publish(ctx, 2), context.DeadlineExceeded)
+	})
+
+	t.Run("Unsubscribe releases blocked publisher", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		s := b.Subscribe(SubscribeOptions{Buffer: 1, Policy: Block})
+		assert.NoError(t, b.Publish(context.Background(), 1))
+
+		published := Go(context.Background(), func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, b.Publish(ctx, 2)
+		})
+		time.Sleep(10 * time.Millisecond)
+		s.Unsubscribe()
+
+		_, err := published.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, b.Len())
+	})
+
+	t.Run("Context cancellation unsubscribes", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		s := b.Subscribe(SubscribeOptions{Context: ctx})
+
+		cancel()
+		<-s.Done()
+
+		_, ok := <-s.C()
+		assert.False(t, ok)
+		assert.Zero(t, b.Len())
+	})
+
+	t.Run("Close ends all subscriptions", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		s := b.Subscribe(SubscribeOptions{})
+
+		b.Close()
+
+		_, ok := <-s.C()
+		assert.False(t, ok)
+		assert.ErrorIs(t, b.Publish(context.Background(), 1), ErrBroadcasterClosed)
+
+		late := b.Subscribe(SubscribeOptions{})
+		_, ok = <-late.C()
+		assert.False(t, ok)
+	})
+
+	t.Run("Concurrent publishers and subscribers", func(t *testing.T) {
+		b := NewBroadcaster[int]()
+		var wg sync.WaitGroup
+
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s := b.Subscribe(SubscribeOptions{Buffer: 4, Policy: DropOldest})
+				for range 10 {
+					select {
+					case <-s.C():
+					case <-time.After(time.Millisecond):
+					}
+				}
+				s.Unsubscribe()
+			}()
+		}
+		for i := range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 50 {
+					_ = b.Publish(context.Background(), i*100+j)
+				}
+			}()
+		}
+
+		wg.Wait()
+		b.Close()
+	})
+}
@@ -0,0 +1,168 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Edge selects on which side of a burst a debounced function is called
+type Edge byte
+
+const (
+	Trailing Edge = 1 << iota // Call the function once the burst settles (default)
+	Leading                   // Call the function immediately on the first trigger of a burst
+)
+
+// DebounceOptions configures a debouncer
+type DebounceOptions struct {
+	Context context.Context
+	Wait    time.Duration
+	MaxWait time.Duration
+	Edge    Edge
+}
+
+// Debouncer coalesces bursts of triggers into a single call of the function
+type Debouncer struct {
+	fn      func()
+	wait    time.Duration
+	maxWait time.Duration
+	edge    Edge
+
+	mu         sync.Mutex
+	timer      *time.Timer
+	generation uint64 // Incremented on each (re)schedule, so stale timers are ignored
+	burstStart time.Time
+	pending    bool
+	stopped    bool
+	stopCtx    func() bool
+}
+
+// NewDebouncer creates a new debouncer calling fn after Wait has passed without triggers.
+// If MaxWait is positive, a pending call is never delayed more than MaxWait from the start of the burst.
+// The debouncer is stopped when the context is cancelled.
+func NewDebouncer(fn func(), opts DebounceOptions) *Debouncer {
+	// Use the trailing edge if none is specified
+	edge := opts.Edge
+	if edge == 0 {
+		edge = Trailing
+	}
+
+	// Create the debouncer
+	d := &Debouncer{
+		fn:      fn,
+		wait:    opts.Wait,
+		maxWait: opts.MaxWait,
+		edge:    edge,
+	}
+
+	// Stop the debouncer when the context is cancelled
+	if opts.Context != nil {
+		d.mu.Lock()
+		d.stopCtx = context.AfterFunc(opts.Context, d.Stop)
+		d.mu.Unlock()
+	}
+
+	// Return the debouncer
+	return d
+}
+
+// Trigger registers an event, (re)starting the wait period
+func (d *Debouncer) Trigger() {
+	d.mu.Lock()
+
+	// Ignore triggers once stopped
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	callNow := false
+
+	// Start a new burst, or extend the current one
+	if d.timer == nil {
+		d.burstStart = now
+		callNow = d.edge&Leading != 0
+		d.pending = !callNow && d.edge&Trailing != 0
+	} else {
+		d.pending = d.edge&Trailing != 0
+	}
+
+	// Compute the delay, without exceeding the max wait of the burst
+	delay := d.wait
+	if d.maxWait > 0 {
+		delay = max(min(delay, d.burstStart.Add(d.maxWait).Sub(now)), 0)
+	}
+	d.schedule(delay)
+	d.mu.Unlock()
+
+	// Call the function on the leading edge
+	if callNow {
+		d.fn()
+	}
+}
+
+// Flush immediately calls the function if a trailing call is pending, ending the current burst
+func (d *Debouncer) Flush() {
+	d.mu.Lock()
+	run := d.pending && !d.stopped
+	d.reset()
+	d.mu.Unlock()
+
+	if run {
+		d.fn()
+	}
+}
+
+// Stop discards any pending call and ignores all future triggers
+func (d *Debouncer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stopped = true
+	d.reset()
+
+	// Release the context watcher
+	if d.stopCtx != nil {
+		d.stopCtx()
+	}
+}
+
+// fire ends the burst and calls the function if a trailing call is pending
+func (d *Debouncer) fire(generation uint64) {
+	d.mu.Lock()
+
+	// Ignore stale timers
+	if generation != d.generation || d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	run := d.pending
+	d.reset()
+	d.mu.Unlock()
+
+	if run {
+		d.fn()
+	}
+}
+
+// schedule (re)starts the timer with the given delay (must hold the lock)
+func (d *Debouncer) schedule(delay time.Duration) {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.generation++
+	generation := d.generation
+	d.timer = time.AfterFunc(delay, func() { d.fire(generation) })
+}
+
+// reset stops the timer and clears the burst state (must hold the lock)
+func (d *Debouncer) reset() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.generation++
+	d.pending = false
+}
@@ -0,0 +1,134 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebouncer(t *testing.T) {
+	t.Run("Trailing edge coalesces a burst", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 30 * time.Millisecond})
+
+		for range 5 {
+			d.Trigger()
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.Zero(t, calls.Load())
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Leading edge calls immediately once per burst", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 30 * time.Millisecond, Edge: Leading})
+
+		for range 5 {
+			d.Trigger()
+		}
+		assert.Equal(t, int32(1), calls.Load())
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(1), calls.Load())
+
+		d.Trigger()
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Both edges", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 20 * time.Millisecond, Edge: Leading | Trailing})
+
+		d.Trigger()
+		d.Trigger()
+		assert.Equal(t, int32(1), calls.Load())
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Single trigger with both edges calls once", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 20 * time.Millisecond, Edge: Leading | Trailing})
+
+		d.Trigger()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Max wait bounds the delay", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 30 * time.Millisecond, MaxWait: 50 * time.Millisecond})
+
+		deadline := time.Now().Add(120 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			d.Trigger()
+			time.Sleep(5 * time.Millisecond)
+		}
+		d.Stop()
+
+		assert.GreaterOrEqual(t, calls.Load(), int32(2))
+	})
+
+	t.Run("Flush calls pending immediately", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: time.Hour})
+
+		d.Trigger()
+		d.Flush()
+		assert.Equal(t, int32(1), calls.Load())
+
+		d.Flush()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Stop discards pending call", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 10 * time.Millisecond})
+
+		d.Trigger()
+		d.Stop()
+		d.Trigger()
+		time.Sleep(30 * time.Millisecond)
+
+		assert.Zero(t, calls.Load())
+	})
+
+	t.Run("Context cancellation stops the debouncer", func(t *testing.T) {
+		var calls atomic.Int32
+		ctx, cancel := context.WithCancel(context.Background())
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Context: ctx, Wait: 10 * time.Millisecond})
+
+		d.Trigger()
+		cancel()
+		time.Sleep(30 * time.Millisecond)
+		d.Trigger()
+		time.Sleep(30 * time.Millisecond)
+
+		assert.Zero(t, calls.Load())
+	})
+
+	t.Run("Concurrent triggers", func(t *testing.T) {
+		var calls atomic.Int32
+		d := NewDebouncer(func() { calls.Add(1) }, DebounceOptions{Wait: 20 * time.Millisecond})
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.Trigger()
+			}()
+		}
+		wg.Wait()
+		time.Sleep(60 * time.Millisecond)
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
@@ -0,0 +1,250 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+)
+
+var (
+	ErrNoFutures = errors.New("no futures provided")
+)
+
+// Future holds the eventual result of an asynchronous computation
+type Future[T any] struct {
+	done  chan struct{}
+	once  sync.Once
+	value T
+	err   error
+}
+
+// newFuture creates a new unsettled Future
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{
+		done: make(chan struct{}),
+	}
+}
+
+// Go starts the function in a new goroutine and returns a Future for its result
+func Go[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Create the future
+	f := newFuture[T]()
+
+	// Run the function and settle the future with its result
+	go func() {
+		f.settle(fn(ctx))
+	}()
+
+	// Return the future
+	return f
+}
+
+// Resolved returns an already settled Future holding the value
+func Resolved[T any](value T) *Future[T] {
+	f := newFuture[T]()
+	f.settle(value, nil)
+	return f
+}
+
+// Rejected returns an already settled Future holding the error
+func Rejected[T any](err error) *Future[T] {
+	var zero T
+	f := newFuture[T]()
+	f.settle(zero, err)
+	return f
+}
+
+// Await blocks until the future is settled or the context is cancelled
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Wait for the result or the cancellation of the context
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed when the future is settled
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// IsDone returns the settled status of the future at the current moment of time (non-blocking)
+func (f *Future[T]) IsDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// settle stores the result and releases all waiters (only the first call has any effect)
+func (f *Future[T]) settle(value T, err error) {
+	f.once.Do(func() {
+		f.value, f.err = value, err
+		close(f.done)
+	})
+}
+
+// Promise is the writable side of a Future, settled manually by the producer
+type Promise[T any] struct {
+	future *Future[T]
+}
+
+// NewPromise creates a new unsettled Promise
+func NewPromise[T any]() *Promise[T] {
+	return &Promise[T]{
+		future: newFuture[T](),
+	}
+}
+
+// Resolve settles the promise with the value (subsequent calls are ignored)
+func (p *Promise[T]) Resolve(value T) {
+	p.future.settle(value, nil)
+}
+
+// Reject settles the promise with the error (subsequent calls are ignored)
+func (p *Promise[T]) Reject(err error) {
+	var zero T
+	p.future.settle(zero, err)
+}
+
+// Future returns the read side of the promise
+func (p *Promise[T]) Future() *Future[T] {
+	return p.future
+}
+
+// Then returns a Future that applies fn to the result of f once it succeeds (errors are propagated unchanged)
+func Then[T, R any](ctx context.Context, f *Future[T], fn func(context.Context, T) (R, error)) *Future[R] {
+	return Go(ctx, func(ctx context.Context) (R, error) {
+		// Wait for the source future
+		value, err := f.Await(ctx)
+		if err != nil {
+			var zero R
+			return zero, err
+		}
+
+		// Apply the continuation
+		return fn(ctx, value)
+	})
+}
+
+// All returns a Future that resolves with all values in order, or rejects with the first error that occurs
+func All[T any](ctx context.Context, futures ...*Future[T]) *Future[[]T] {
+	return Go(ctx, func(ctx context.Context) ([]T, error) {
+		// Create the results slice
+		values := make([]T, len(futures))
+
+		// Wait for the futures in completion order
+		for index, f := range settled(ctx, futures) {
+			if f.err != nil {
+				return nil, f.err
+			}
+			values[index] = f.value
+		}
+
+		// Check if the wait was interrupted by the context
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Return the collected values
+		return values, nil
+	})
+}
+
+// Any returns a Future that resolves with the first successful value, or rejects with all errors joined
+func Any[T any](ctx context.Context, futures ...*Future[T]) *Future[T] {
+	return Go(ctx, func(ctx context.Context) (T, error) {
+		var zero T
+
+		// Check if there is anything to wait for
+		if len(futures) == 0 {
+			return zero, ErrNoFutures
+		}
+
+		// Wait for the futures in completion order
+		errs := make([]error, 0, len(futures))
+		for _, f := range settled(ctx, futures) {
+			if f.err == nil {
+				return f.value, nil
+			}
+			errs = append(errs, f.err)
+		}
+
+		// Check if the wait was interrupted by the context
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		// Return all the errors
+		return zero, errors.Join(errs...)
+	})
+}
+
+// Race returns a Future that settles with the result of the first future to settle
+func Race[T any](ctx context.Context, futures ...*Future[T]) *Future[T] {
+	return Go(ctx, func(ctx context.Context) (T, error) {
+		var zero T
+
+		// Check if there is anything to wait for
+		if len(futures) == 0 {
+			return zero, ErrNoFutures
+		}
+
+		// Return the first settled result
+		for _, f := range settled(ctx, futures) {
+			return f.value, f.err
+		}
+
+		// The wait was interrupted by the context
+		return zero, ctx.Err()
+	})
+}
+
+// settled yields the futures as they settle (with their index), stopping early if the context is cancelled
+func settled[T any](ctx context.Context, futures []*Future[T]) iter.Seq2[int, *Future[T]] {
+	return func(yield func(int, *Future[T]) bool) {
+		// Stop the watchers when iteration ends
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Spawn a watcher for each future (buffered so watchers never block)
+		indexes := make(chan int, len(futures))
+		for index, f := range futures {
+			go func() {
+				select {
+				case <-f.done:
+					indexes <- index
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		// Yield the futures in completion order
+		for range futures {
+			select {
+			case index := <-indexes:
+				if !yield(index, futures[index]) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
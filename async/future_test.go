@@ -0,0 +1,153 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuture(t *testing.T) {
+	t.Run("Go resolves with value", func(t *testing.T) {
+		f := Go(context.Background(), func(ctx context.Context) (int, error) {
+			return 42, nil
+		})
+
+		value, err := f.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+		assert.True(t, f.IsDone())
+	})
+
+	t.Run("Go rejects with error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		f := Go(context.Background(), func(ctx context.Context) (int, error) {
+			return 0, errBoom
+		})
+
+		_, err := f.Await(context.Background())
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("Await respects context cancellation", func(t *testing.T) {
+		f := NewPromise[int]().Future()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := f.Await(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, f.IsDone())
+	})
+
+	t.Run("Promise settles only once", func(t *testing.T) {
+		p := NewPromise[string]()
+		p.Resolve("first")
+		p.Resolve("second")
+		p.Reject(errors.New("ignored"))
+
+		value, err := p.Future().Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "first", value)
+	})
+
+	t.Run("Then chains values", func(t *testing.T) {
+		f := Then(context.Background(), Resolved(20), func(ctx context.Context, v int) (string, error) {
+			return "value-" + strconv.Itoa(v), nil
+		})
+
+		value, err := f.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "value-20", value)
+	})
+
+	t.Run("Then propagates errors without calling continuation", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		called := false
+		f := Then(context.Background(), Rejected[int](errBoom), func(ctx context.Context, v int) (int, error) {
+			called = true
+			return v, nil
+		})
+
+		_, err := f.Await(context.Background())
+		assert.ErrorIs(t, err, errBoom)
+		assert.False(t, called)
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Run("Resolves values in order", func(t *testing.T) {
+		slow := Go(context.Background(), func(ctx context.Context) (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			return 1, nil
+		})
+
+		values, err := All(context.Background(), slow, Resolved(2), Resolved(3)).Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, values)
+	})
+
+	t.Run("Fails fast on first error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		never := NewPromise[int]().Future()
+
+		_, err := All(context.Background(), never, Rejected[int](errBoom)).Await(context.Background())
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("Empty resolves to empty slice", func(t *testing.T) {
+		values, err := All[int](context.Background()).Await(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("Cancelled context rejects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		f := All(ctx, NewPromise[int]().Future())
+		cancel()
+
+		_, err := f.Await(context.Background())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAny(t *testing.T) {
+	t.Run("Resolves with first success", func(t *testing.T) {
+		value, err := Any(context.Background(), Rejected[int](errors.New("a")), Resolved(7)).Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 7, value)
+	})
+
+	t.Run("Joins all errors", func(t *testing.T) {
+		errA, errB := errors.New("a"), errors.New("b")
+
+		_, err := Any(context.Background(), Rejected[int](errA), Rejected[int](errB)).Await(context.Background())
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+	})
+
+	t.Run("No futures", func(t *testing.T) {
+		_, err := Any[int](context.Background()).Await(context.Background())
+		assert.ErrorIs(t, err, ErrNoFutures)
+	})
+}
+
+func TestRace(t *testing.T) {
+	t.Run("Settles with first result", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		slow := Go(context.Background(), func(ctx context.Context) (int, error) {
+			time.Sleep(50 * time.Millisecond)
+			return 1, nil
+		})
+
+		_, err := Race(context.Background(), slow, Rejected[int](errBoom)).Await(context.Background())
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("No futures", func(t *testing.T) {
+		_, err := Race[int](context.Background()).Await(context.Background())
+		assert.ErrorIs(t, err, ErrNoFutures)
+	})
+}
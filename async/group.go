@@ -0,0 +1,152 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/r3dpixel/toolkit/trace"
+)
+
+// GroupOptions configures a task group
+type GroupOptions struct {
+	Context     context.Context
+	Parallelism int
+}
+
+// Group runs functions concurrently, cancels the remaining ones on the first error,
+// and collects the results in submission order
+type Group[T any] struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	wg      sync.WaitGroup
+	slots   chan struct{}
+	mu      sync.Mutex
+	results []T
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup creates a new task group with the given options (a non-positive parallelism means no limit)
+func NewGroup[T any](opts GroupOptions) *Group[T] {
+	// Use a background context if none is provided
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Create the group context (cancelled with the first error)
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	// Create the group
+	g := &Group[T]{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	// Limit the number of concurrent functions if requested
+	if opts.Parallelism > 0 {
+		g.slots = make(chan struct{}, opts.Parallelism)
+	}
+
+	// Return the group
+	return g
+}
+
+// Context returns the group context, which is cancelled when any function fails or Wait returns
+func (g *Group[T]) Context() context.Context {
+	return g.ctx
+}
+
+// Go submits the function to the group, blocking while the parallelism limit is reached.
+// The function is skipped if the group is cancelled before it can start.
+func (g *Group[T]) Go(fn func(context.Context) (T, error)) {
+	// Reserve the result slot (keeps submission order)
+	var zero T
+	g.mu.Lock()
+	index := len(g.results)
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	// Skip the function if the group is already cancelled
+	if IsCancelled(g.ctx) {
+		return
+	}
+
+	// Wait for a free slot if the parallelism is limited
+	if g.slots != nil {
+		select {
+		case g.slots <- struct{}{}:
+		case <-g.ctx.Done():
+			return
+		}
+	}
+
+	// Run the function
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		// Release the slot when done
+		if g.slots != nil {
+			defer func() { <-g.slots }()
+		}
+
+		// Run the function and store the result
+		value, err := g.run(fn)
+		if err != nil {
+			g.fail(err)
+			return
+		}
+		g.mu.Lock()
+		g.results[index] = value
+		g.mu.Unlock()
+	}()
+}
+
+// Wait blocks until all submitted functions return, then returns the results in submission order and the first error.
+// Results of functions that failed or were skipped are left as zero values.
+func (g *Group[T]) Wait() ([]T, error) {
+	// Wait for all functions to finish
+	g.wg.Wait()
+
+	// Release the group context
+	g.cancel(nil)
+
+	// Return the results
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.results, g.err
+}
+
+// run calls the function, converting any panic into an error carrying the stack trace
+func (g *Group[T]) run(fn func(context.Context) (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+	}()
+	return fn(g.ctx)
+}
+
+// fail records the first error and cancels the group
+func (g *Group[T]) fail(err error) {
+	g.errOnce.Do(func() {
+		g.mu.Lock()
+		g.err = err
+		g.mu.Unlock()
+		g.cancel(err)
+	})
+}
+
+// panicError converts a recovered panic value into a traced error carrying the stack trace
+func panicError(r any) error {
+	// Keep the original error if the panic value is one
+	cause, ok := r.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", r)
+	}
+
+	// Return the traced error
+	return trace.Error().Msg("recovered panic").Field(trace.STACK, string(debug.Stack())).Wrap(cause)
+}
@@ -0,0 +1,115 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("Collects results in submission order", func(t *testing.T) {
+		g := NewGroup[int](GroupOptions{})
+		for i := range 5 {
+			g.Go(func(ctx context.Context) (int, error) {
+				time.Sleep(time.Duration(5-i) * time.Millisecond)
+				return i * i, nil
+			})
+		}
+
+		results, err := g.Wait()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 4, 9, 16}, results)
+	})
+
+	t.Run("First error cancels siblings", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		g := NewGroup[int](GroupOptions{})
+
+		g.Go(func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		g.Go(func(ctx context.Context) (int, error) {
+			return 0, errBoom
+		})
+
+		_, err := g.Wait()
+		assert.ErrorIs(t, err, errBoom)
+		assert.ErrorIs(t, context.Cause(g.Context()), errBoom)
+	})
+
+	t.Run("Panics are converted into traced errors", func(t *testing.T) {
+		g := NewGroup[int](GroupOptions{})
+		g.Go(func(ctx context.Context) (int, error) {
+			panic("something went wrong")
+		})
+
+		_, err := g.Wait()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "something went wrong")
+
+		var tracedErr *trace.Err
+		assert.True(t, errors.As(err, &tracedErr))
+		assert.True(t, tracedErr.HasField(trace.STACK))
+	})
+
+	t.Run("Panics with errors keep the original error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		g := NewGroup[int](GroupOptions{})
+		g.Go(func(ctx context.Context) (int, error) {
+			panic(errBoom)
+		})
+
+		_, err := g.Wait()
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("Respects parallelism limit", func(t *testing.T) {
+		const parallelism = 3
+		var concurrent, maxConcurrent atomic.Int32
+
+		g := NewGroup[struct{}](GroupOptions{Parallelism: parallelism})
+		for range 20 {
+			g.Go(func(ctx context.Context) (struct{}, error) {
+				cur := concurrent.Add(1)
+				for {
+					m := maxConcurrent.Load()
+					if cur <= m || maxConcurrent.CompareAndSwap(m, cur) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				concurrent.Add(-1)
+				return struct{}{}, nil
+			})
+		}
+
+		_, err := g.Wait()
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, maxConcurrent.Load(), int32(parallelism))
+	})
+
+	t.Run("Skips functions after cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var calls atomic.Int32
+		g := NewGroup[int](GroupOptions{Context: ctx, Parallelism: 1})
+		for range 3 {
+			g.Go(func(ctx context.Context) (int, error) {
+				calls.Add(1)
+				return 1, nil
+			})
+		}
+
+		results, err := g.Wait()
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Zero(t, calls.Load())
+	})
+}
@@ -0,0 +1,116 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// keyedLock is the lock of a single key, shared by all goroutines holding or waiting for it
+type keyedLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// KeyedMutex serializes operations per key, without a global lock held during the operations.
+// Locks of idle keys are freed automatically.
+type KeyedMutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedLock
+}
+
+// NewKeyedMutex creates a new keyed mutex
+func NewKeyedMutex[K comparable]() *KeyedMutex[K] {
+	return &KeyedMutex[K]{
+		locks: make(map[K]*keyedLock),
+	}
+}
+
+// Lock blocks until the key is locked or the context is cancelled
+func (m *KeyedMutex[K]) Lock(ctx context.Context, key K) error {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Reference the lock of the key
+	m.mu.Lock()
+	l := m.acquire(key)
+	m.mu.Unlock()
+
+	// Wait for the lock or the cancellation of the context
+	select {
+	case l.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		m.release(key, l)
+		m.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryLock locks the key without blocking, returning false if it is already locked
+func (m *KeyedMutex[K]) TryLock(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Reference the lock of the key
+	l := m.acquire(key)
+
+	// Try to lock the key
+	select {
+	case l.ch <- struct{}{}:
+		return true
+	default:
+		m.release(key, l)
+		return false
+	}
+}
+
+// Unlock unlocks the key (panics if the key is not locked)
+func (m *KeyedMutex[K]) Unlock(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Find the lock of the key
+	l, exists := m.locks[key]
+	if !exists {
+		panic("async: unlock of unlocked key")
+	}
+
+	// Unlock the key
+	select {
+	case <-l.ch:
+	default:
+		panic("async: unlock of unlocked key")
+	}
+
+	// Drop the reference of the holder
+	m.release(key, l)
+}
+
+// Len returns the number of keys currently locked or waited for
+func (m *KeyedMutex[K]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.locks)
+}
+
+// acquire returns the lock of the key, creating it if needed, and adds a reference to it (must hold the global lock)
+func (m *KeyedMutex[K]) acquire(key K) *keyedLock {
+	l, exists := m.locks[key]
+	if !exists {
+		l = &keyedLock{ch: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	return l
+}
+
+// release drops a reference to the lock, freeing it when idle (must hold the global lock)
+func (m *KeyedMutex[K]) release(key K, l *keyedLock) {
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
@@ -0,0 +1,77 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	t.Run("Serializes the same key", func(t *testing.T) {
+		m := NewKeyedMutex[string]()
+		counter := 0
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, m.Lock(context.Background(), "path"))
+				current := counter
+				time.Sleep(100 * time.Microsecond)
+				counter = current + 1
+				m.Unlock("path")
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 50, counter)
+	})
+
+	t.Run("Different keys do not block each other", func(t *testing.T) {
+		m := NewKeyedMutex[string]()
+
+		assert.NoError(t, m.Lock(context.Background(), "a"))
+		assert.True(t, m.TryLock("b"))
+		assert.False(t, m.TryLock("a"))
+
+		m.Unlock("a")
+		m.Unlock("b")
+	})
+
+	t.Run("Idle keys are freed", func(t *testing.T) {
+		m := NewKeyedMutex[int]()
+
+		for i := range 10 {
+			assert.NoError(t, m.Lock(context.Background(), i))
+		}
+		assert.Equal(t, 10, m.Len())
+
+		for i := range 10 {
+			m.Unlock(i)
+		}
+		assert.Zero(t, m.Len())
+	})
+
+	t.Run("Context cancellation aborts the wait", func(t *testing.T) {
+		m := NewKeyedMutex[string]()
+		assert.NoError(t, m.Lock(context.Background(), "key"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, m.Lock(ctx, "key"), context.DeadlineExceeded)
+		assert.Equal(t, 1, m.Len())
+
+		m.Unlock("key")
+		assert.Zero(t, m.Len())
+	})
+
+	t.Run("Unlock of unlocked key panics", func(t *testing.T) {
+		m := NewKeyedMutex[string]()
+		assert.Panics(t, func() { m.Unlock("missing") })
+	})
+}
@@ -0,0 +1,216 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultRetryAttempts = 5
+	defaultMinBackoff    = 10 * time.Millisecond
+	defaultMaxBackoff    = 500 * time.Millisecond
+)
+
+var (
+	ErrRetryExhausted = errors.New("retry attempts exhausted")
+)
+
+// Backoff computes the delay before the next attempt (attempt starts at 1, previous is the last delay or 0)
+type Backoff func(attempt int, previous time.Duration) time.Duration
+
+// RetryAttempt describes a failed attempt that is about to be retried
+type RetryAttempt struct {
+	Number  int
+	Err     error
+	Delay   time.Duration
+	Elapsed time.Duration
+}
+
+// RetryPolicy configures the retry behavior.
+// If both MaxAttempts and MaxElapsed are non-positive, the number of attempts defaults to 5.
+type RetryPolicy struct {
+	Backoff     Backoff
+	MaxAttempts int
+	MaxElapsed  time.Duration
+	Retryable   func(error) bool
+	OnRetry     func(RetryAttempt)
+}
+
+// permanentErr marks an error that must not be retried
+type permanentErr struct {
+	err error
+}
+
+// Error returns the message of the wrapped error
+func (e *permanentErr) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *permanentErr) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps the error so that Retry stops immediately and returns it
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentErr{err: err}
+}
+
+// Retry calls fn until it succeeds, the policy gives up, or the context is cancelled
+func Retry(ctx context.Context, policy RetryPolicy, fn func(context.Context) error) error {
+	_, err := RetryValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// RetryValue calls fn until it succeeds, the policy gives up, or the context is cancelled, returning the value produced
+func RetryValue[T any](ctx context.Context, policy RetryPolicy, fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Set default values if needed
+	if policy.Backoff == nil {
+		policy.Backoff = ExponentialBackoff(defaultMinBackoff, defaultMaxBackoff)
+	}
+	if policy.MaxAttempts <= 0 && policy.MaxElapsed <= 0 {
+		policy.MaxAttempts = defaultRetryAttempts
+	}
+
+	start := time.Now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		// Call the function
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		// Stop on permanent or non-retryable errors
+		var permanent *permanentErr
+		if errors.As(err, &permanent) {
+			return zero, permanent.err
+		}
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return zero, err
+		}
+
+		// Compute the next delay
+		delay = max(policy.Backoff(attempt, delay), 0)
+		elapsed := time.Since(start)
+
+		// Stop if the policy limits are reached
+		if (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) ||
+			(policy.MaxElapsed > 0 && elapsed+delay > policy.MaxElapsed) {
+			return zero, trace.Error().
+				Field(trace.ATTEMPT, attempt).
+				Field(trace.ELAPSED, elapsed).
+				Wrap(fmt.Errorf("%w: %w", ErrRetryExhausted, err))
+		}
+
+		// Notify the hook
+		if policy.OnRetry != nil {
+			policy.OnRetry(RetryAttempt{Number: attempt, Err: err, Delay: delay, Elapsed: elapsed})
+		}
+
+		// Wait for the delay or the cancellation of the context
+		if ctxErr := sleep(ctx, delay); ctxErr != nil {
+			return zero, trace.Error().
+				Field(trace.ATTEMPT, attempt).
+				Field(trace.ELAPSED, time.Since(start)).
+				Wrap(fmt.Errorf("%w: %w", ctxErr, err))
+		}
+	}
+}
+
+// LogRetry returns a RetryPolicy.OnRetry hook that logs each failed attempt (attaches fields to log)
+func LogRetry(msg string, fields map[string]any) func(RetryAttempt) {
+	return func(attempt RetryAttempt) {
+		log.Warn().
+			Fields(fields).
+			Int(trace.ATTEMPT, attempt.Number).
+			Dur("delay", attempt.Delay).
+			Err(attempt.Err).
+			Msg(msg)
+	}
+}
+
+// ConstantBackoff waits the same delay between all attempts
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay on each attempt, starting from minDelay and capped at maxDelay
+func ExponentialBackoff(minDelay, maxDelay time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		return exponential(attempt, minDelay, maxDelay)
+	}
+}
+
+// FullJitterBackoff waits a random delay between 0 and the exponential delay
+func FullJitterBackoff(minDelay, maxDelay time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		return randomBetween(0, exponential(attempt, minDelay, maxDelay))
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random delay between minDelay and three times the previous delay, capped at maxDelay
+func DecorrelatedJitterBackoff(minDelay, maxDelay time.Duration) Backoff {
+	return func(_ int, previous time.Duration) time.Duration {
+		return min(randomBetween(minDelay, max(previous, minDelay)*3), maxDelay)
+	}
+}
+
+// exponential returns minDelay * 2^(attempt-1), capped at maxDelay (overflow safe)
+func exponential(attempt int, minDelay, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		// Stop doubling before the delay could overflow
+		if delay > maxDelay/2 {
+			return maxDelay
+		}
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// randomBetween returns a random duration in [lo, hi]
+func randomBetween(lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + rand.N(hi-lo+1)
+}
+
+// sleep waits for the delay, returning the context error if it is cancelled first
+func sleep(ctx context.Context, delay time.Duration) error {
+	// Return immediately for non-positive delays (still honoring cancellation)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	// Wait for the timer or the cancellation of the context
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
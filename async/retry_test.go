@@ -0,0 +1,157 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	errBoom := errors.New("boom")
+	fast := ConstantBackoff(time.Millisecond)
+
+	t.Run("Succeeds after failures", func(t *testing.T) {
+		calls := 0
+		value, err := RetryValue(context.Background(), RetryPolicy{Backoff: fast, MaxAttempts: 5}, func(ctx context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errBoom
+			}
+			return calls, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, value)
+	})
+
+	t.Run("Stops after max attempts", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), RetryPolicy{Backoff: fast, MaxAttempts: 3}, func(ctx context.Context) error {
+			calls++
+			return errBoom
+		})
+
+		assert.Equal(t, 3, calls)
+		assert.ErrorIs(t, err, ErrRetryExhausted)
+		assert.ErrorIs(t, err, errBoom)
+
+		var tracedErr *trace.Err
+		assert.True(t, errors.As(err, &tracedErr))
+		assert.Equal(t, 3, tracedErr.GetField(trace.ATTEMPT))
+	})
+
+	t.Run("Stops after max elapsed time", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), RetryPolicy{Backoff: ConstantBackoff(10 * time.Millisecond), MaxElapsed: 35 * time.Millisecond}, func(ctx context.Context) error {
+			calls++
+			return errBoom
+		})
+
+		assert.ErrorIs(t, err, ErrRetryExhausted)
+		assert.GreaterOrEqual(t, calls, 2)
+		assert.LessOrEqual(t, calls, 4)
+	})
+
+	t.Run("Non-retryable errors stop immediately", func(t *testing.T) {
+		calls := 0
+		policy := RetryPolicy{
+			Backoff:     fast,
+			MaxAttempts: 5,
+			Retryable:   func(err error) bool { return !errors.Is(err, errBoom) },
+		}
+		err := Retry(context.Background(), policy, func(ctx context.Context) error {
+			calls++
+			return errBoom
+		})
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, errBoom, err)
+	})
+
+	t.Run("Permanent errors stop immediately", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), RetryPolicy{Backoff: fast, MaxAttempts: 5}, func(ctx context.Context) error {
+			calls++
+			return Permanent(errBoom)
+		})
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, errBoom, err)
+	})
+
+	t.Run("Hook is called for each retry", func(t *testing.T) {
+		var attempts []RetryAttempt
+		policy := RetryPolicy{
+			Backoff:     fast,
+			MaxAttempts: 3,
+			OnRetry:     func(a RetryAttempt) { attempts = append(attempts, a) },
+		}
+		_ = Retry(context.Background(), policy, func(ctx context.Context) error {
+			return errBoom
+		})
+
+		assert.Len(t, attempts, 2)
+		assert.Equal(t, 1, attempts[0].Number)
+		assert.Equal(t, 2, attempts[1].Number)
+		assert.ErrorIs(t, attempts[0].Err, errBoom)
+		assert.Equal(t, time.Millisecond, attempts[0].Delay)
+	})
+
+	t.Run("Context cancellation interrupts the wait", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := Retry(ctx, RetryPolicy{Backoff: ConstantBackoff(time.Hour), MaxAttempts: 5}, func(ctx context.Context) error {
+			return errBoom
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, errBoom)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Constant", func(t *testing.T) {
+		b := ConstantBackoff(50 * time.Millisecond)
+		assert.Equal(t, 50*time.Millisecond, b(1, 0))
+		assert.Equal(t, 50*time.Millisecond, b(10, 50*time.Millisecond))
+	})
+
+	t.Run("Exponential", func(t *testing.T) {
+		b := ExponentialBackoff(10*time.Millisecond, 100*time.Millisecond)
+		assert.Equal(t, 10*time.Millisecond, b(1, 0))
+		assert.Equal(t, 20*time.Millisecond, b(2, 0))
+		assert.Equal(t, 40*time.Millisecond, b(3, 0))
+		assert.Equal(t, 80*time.Millisecond, b(4, 0))
+		assert.Equal(t, 100*time.Millisecond, b(5, 0))
+		assert.Equal(t, 100*time.Millisecond, b(1000, 0))
+	})
+
+	t.Run("Exponential does not overflow", func(t *testing.T) {
+		b := ExponentialBackoff(time.Second, time.Duration(1<<62))
+		assert.Equal(t, time.Duration(1<<62), b(200, 0))
+	})
+
+	t.Run("Full jitter stays within bounds", func(t *testing.T) {
+		b := FullJitterBackoff(10*time.Millisecond, 100*time.Millisecond)
+		for attempt := 1; attempt < 50; attempt++ {
+			delay := b(attempt, 0)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, 100*time.Millisecond)
+		}
+	})
+
+	t.Run("Decorrelated jitter stays within bounds", func(t *testing.T) {
+		b := DecorrelatedJitterBackoff(10*time.Millisecond, 100*time.Millisecond)
+		var previous time.Duration
+		for attempt := 1; attempt < 50; attempt++ {
+			previous = b(attempt, previous)
+			assert.GreaterOrEqual(t, previous, 10*time.Millisecond)
+			assert.LessOrEqual(t, previous, 100*time.Millisecond)
+		}
+	})
+}
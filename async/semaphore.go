@@ -0,0 +1,141 @@
+package async
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrWeightExceedsCapacity = errors.New("requested weight exceeds semaphore capacity")
+)
+
+// waiter is a pending Acquire call of a Semaphore
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Semaphore limits concurrent access to a shared budget (weights), serving waiters in FIFO order
+type Semaphore struct {
+	capacity int64
+	used     int64
+	mu       sync.Mutex
+	waiters  list.List
+}
+
+// NewSemaphore creates a new semaphore with the given total weight (for example a bytex.Size budget)
+func NewSemaphore(capacity int64) *Semaphore {
+	return &Semaphore{
+		capacity: capacity,
+	}
+}
+
+// Acquire blocks until a weight of n is available or the context is cancelled.
+// Waiters are served in FIFO order, so a large request is never starved by smaller ones.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s.mu.Lock()
+
+	// Acquire immediately if there is room and nobody is queued
+	if s.capacity-s.used >= n && s.waiters.Len() == 0 {
+		s.used += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	// Reject requests that can never be satisfied
+	if n > s.capacity {
+		s.mu.Unlock()
+		return ErrWeightExceedsCapacity
+	}
+
+	// Queue the request
+	w := waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	// Wait to be served or for the cancellation of the context
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Give the weight back if it was granted in the meantime
+	select {
+	case <-w.ready:
+		s.used -= n
+	default:
+		s.waiters.Remove(elem)
+	}
+
+	// Removing (or releasing) may allow the next waiters to proceed
+	s.notifyWaiters()
+	return ctx.Err()
+}
+
+// TryAcquire acquires a weight of n without blocking, returning false if it is not available
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Only acquire if there is room and nobody is queued
+	if s.capacity-s.used >= n && s.waiters.Len() == 0 {
+		s.used += n
+		return true
+	}
+	return false
+}
+
+// Release returns a weight of n to the semaphore
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check for more releases than acquisitions
+	s.used -= n
+	if s.used < 0 {
+		panic("async: semaphore released more than held")
+	}
+
+	// Serve the waiters
+	s.notifyWaiters()
+}
+
+// Available returns the weight that is currently not acquired
+func (s *Semaphore) Available() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.capacity - s.used
+}
+
+// notifyWaiters serves the queued waiters in order while there is room (must hold the lock)
+func (s *Semaphore) notifyWaiters() {
+	for {
+		// Stop when there are no waiters
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		// Stop at the first waiter that does not fit (keeps FIFO order)
+		w := front.Value.(waiter)
+		if s.capacity-s.used < w.n {
+			return
+		}
+
+		// Grant the weight
+		s.used += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,135 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r3dpixel/toolkit/bytex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemaphore(t *testing.T) {
+	t.Run("Acquire and release by weight", func(t *testing.T) {
+		s := NewSemaphore(int64(10 * bytex.MiB))
+
+		assert.NoError(t, s.Acquire(context.Background(), int64(4*bytex.MiB)))
+		assert.NoError(t, s.Acquire(context.Background(), int64(6*bytex.MiB)))
+		assert.Zero(t, s.Available())
+		assert.False(t, s.TryAcquire(1))
+
+		s.Release(int64(4 * bytex.MiB))
+		assert.Equal(t, int64(4*bytex.MiB), s.Available())
+		assert.True(t, s.TryAcquire(int64(4*bytex.MiB)))
+	})
+
+	t.Run("Weight over capacity fails", func(t *testing.T) {
+		s := NewSemaphore(5)
+		assert.ErrorIs(t, s.Acquire(context.Background(), 6), ErrWeightExceedsCapacity)
+	})
+
+	t.Run("Context cancellation aborts the wait", func(t *testing.T) {
+		s := NewSemaphore(1)
+		assert.NoError(t, s.Acquire(context.Background(), 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, s.Acquire(ctx, 1), context.DeadlineExceeded)
+
+		s.Release(1)
+		assert.Equal(t, int64(1), s.Available())
+	})
+
+	t.Run("Waiters are served in FIFO order", func(t *testing.T) {
+		s := NewSemaphore(10)
+		assert.NoError(t, s.Acquire(context.Background(), 10))
+
+		var mu sync.Mutex
+		var order []int64
+		var wg sync.WaitGroup
+		for _, n := range []int64{8, 3, 1} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, s.Acquire(context.Background(), n))
+				mu.Lock()
+				order = append(order, n)
+				mu.Unlock()
+				s.Release(n)
+			}()
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// A small request must not jump ahead of the queued large one
+		assert.False(t, s.TryAcquire(1))
+
+		s.Release(10)
+		wg.Wait()
+
+		assert.Equal(t, int64(8), order[0])
+		assert.Equal(t, int64(10), s.Available())
+	})
+
+	t.Run("Cancelled head waiter unblocks the queue", func(t *testing.T) {
+		s := NewSemaphore(10)
+		assert.NoError(t, s.Acquire(context.Background(), 5))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		big := Go(context.Background(), func(context.Context) (struct{}, error) {
+			return struct{}{}, s.Acquire(ctx, 10)
+		})
+		time.Sleep(10 * time.Millisecond)
+
+		small := Go(context.Background(), func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, s.Acquire(ctx, 5)
+		})
+		time.Sleep(10 * time.Millisecond)
+		assert.False(t, small.IsDone())
+
+		cancel()
+		_, err := big.Await(context.Background())
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = small.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, s.Available())
+	})
+
+	t.Run("Concurrent use never exceeds capacity", func(t *testing.T) {
+		const capacity = 6
+		s := NewSemaphore(capacity)
+		var used, maxUsed atomic.Int64
+
+		var wg sync.WaitGroup
+		for i := range 30 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				n := int64(i%3 + 1)
+				assert.NoError(t, s.Acquire(context.Background(), n))
+				cur := used.Add(n)
+				for {
+					m := maxUsed.Load()
+					if cur <= m || maxUsed.CompareAndSwap(m, cur) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				used.Add(-n)
+				s.Release(n)
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, maxUsed.Load(), int64(capacity))
+		assert.Equal(t, int64(capacity), s.Available())
+	})
+
+	t.Run("Releasing more than held panics", func(t *testing.T) {
+		s := NewSemaphore(1)
+		assert.Panics(t, func() { s.Release(1) })
+	})
+}
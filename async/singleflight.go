@@ -0,0 +1,140 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flight is a single in-progress (or cached) call of a SingleFlight
+type flight[V any] struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	settled bool
+	value   V
+	err     error
+}
+
+// SingleFlight collapses concurrent calls for the same key into a single execution, sharing its result.
+// Successful results can optionally be kept for a TTL after the call completes.
+type SingleFlight[K comparable, V any] struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	flights map[K]*flight[V]
+}
+
+// NewSingleFlight creates a new SingleFlight keeping successful results for the TTL (a non-positive TTL disables caching)
+func NewSingleFlight[K comparable, V any](ttl time.Duration) *SingleFlight[K, V] {
+	return &SingleFlight[K, V]{
+		ttl:     ttl,
+		flights: make(map[K]*flight[V]),
+	}
+}
+
+// Do executes the function for the key, unless a call for the same key is in progress or cached,
+// in which case it waits for and returns that shared result.
+// The function runs with a context detached from the callers, and is cancelled only when all waiters gave up.
+func (s *SingleFlight[K, V]) Do(ctx context.Context, key K, fn func(context.Context) (V, error)) (V, error) {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Lock the flights map
+	s.mu.Lock()
+
+	// Join the existing flight if there is one
+	if f, exists := s.flights[key]; exists {
+		f.waiters++
+		s.mu.Unlock()
+		return s.wait(ctx, key, f)
+	}
+
+	// Start a new flight (keeps the values of the context, but not its cancellation)
+	flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	f := &flight[V]{
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		waiters: 1,
+	}
+	s.flights[key] = f
+	s.mu.Unlock()
+
+	// Execute the function
+	go s.run(flightCtx, key, f, fn)
+
+	// Wait for the result
+	return s.wait(ctx, key, f)
+}
+
+// Forget removes the key, so the next call starts a new execution (current waiters still get the old result)
+func (s *SingleFlight[K, V]) Forget(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.flights, key)
+}
+
+// run executes the function and publishes its result to all waiters
+func (s *SingleFlight[K, V]) run(ctx context.Context, key K, f *flight[V], fn func(context.Context) (V, error)) {
+	// Release the flight context when done
+	defer f.cancel()
+
+	// Execute the function (a panic is converted into an error)
+	value, err := func() (value V, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicError(r)
+			}
+		}()
+		return fn(ctx)
+	}()
+
+	// Publish the result
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.value, f.err, f.settled = value, err, true
+	close(f.done)
+
+	// Remove the flight immediately, unless the result must be cached
+	if err != nil || s.ttl <= 0 {
+		s.remove(key, f)
+		return
+	}
+
+	// Remove the cached result once the TTL expires
+	time.AfterFunc(s.ttl, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.remove(key, f)
+	})
+}
+
+// wait blocks until the flight completes or the context is cancelled
+func (s *SingleFlight[K, V]) wait(ctx context.Context, key K, f *flight[V]) (V, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+	}
+
+	// Leave the flight, cancelling it if nobody is waiting anymore
+	s.mu.Lock()
+	f.waiters--
+	if f.waiters == 0 && !f.settled {
+		f.cancel()
+		s.remove(key, f)
+	}
+	s.mu.Unlock()
+
+	// Return the cancellation error
+	var zero V
+	return zero, ctx.Err()
+}
+
+// remove deletes the flight for the key, only if it was not already replaced (must hold the lock)
+func (s *SingleFlight[K, V]) remove(key K, f *flight[V]) {
+	if s.flights[key] == f {
+		delete(s.flights, key)
+	}
+}
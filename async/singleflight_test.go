@@ -0,0 +1,152 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlight(t *testing.T) {
+	t.Run("Collapses concurrent calls", func(t *testing.T) {
+		sf := NewSingleFlight[string, int](0)
+		var calls atomic.Int32
+		release := make(chan struct{})
+
+		var wg sync.WaitGroup
+		results := make([]int, 10)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+					calls.Add(1)
+					<-release
+					return 42, nil
+				})
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		for _, result := range results {
+			assert.Equal(t, 42, result)
+		}
+	})
+
+	t.Run("Different keys run independently", func(t *testing.T) {
+		sf := NewSingleFlight[string, string](0)
+
+		a, err := sf.Do(context.Background(), "a", func(ctx context.Context) (string, error) { return "A", nil })
+		assert.NoError(t, err)
+		b, err := sf.Do(context.Background(), "b", func(ctx context.Context) (string, error) { return "B", nil })
+		assert.NoError(t, err)
+
+		assert.Equal(t, "A", a)
+		assert.Equal(t, "B", b)
+	})
+
+	t.Run("Caches results for the TTL", func(t *testing.T) {
+		sf := NewSingleFlight[string, int](50 * time.Millisecond)
+		var calls atomic.Int32
+		fn := func(ctx context.Context) (int, error) {
+			return int(calls.Add(1)), nil
+		}
+
+		first, _ := sf.Do(context.Background(), "key", fn)
+		second, _ := sf.Do(context.Background(), "key", fn)
+		assert.Equal(t, 1, first)
+		assert.Equal(t, 1, second)
+
+		time.Sleep(100 * time.Millisecond)
+
+		third, _ := sf.Do(context.Background(), "key", fn)
+		assert.Equal(t, 2, third)
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		sf := NewSingleFlight[string, int](time.Minute)
+		errBoom := errors.New("boom")
+
+		_, err := sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 0, errBoom })
+		assert.ErrorIs(t, err, errBoom)
+
+		value, err := sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 7, nil })
+		assert.NoError(t, err)
+		assert.Equal(t, 7, value)
+	})
+
+	t.Run("Forget drops cached result", func(t *testing.T) {
+		sf := NewSingleFlight[string, int](time.Minute)
+
+		_, _ = sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 1, nil })
+		sf.Forget("key")
+		value, _ := sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 2, nil })
+
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("Cancelled waiter leaves, last waiter cancels the call", func(t *testing.T) {
+		sf := NewSingleFlight[string, int](0)
+		cancelled := make(chan struct{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err := sf.Do(ctx, "key", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return 0, ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("the call was not cancelled after all waiters left")
+		}
+	})
+
+	t.Run("Remaining waiters keep the call alive", func(t *testing.T) {
+		sf := NewSingleFlight[string, int](0)
+		release := make(chan struct{})
+		fn := func(ctx context.Context) (int, error) {
+			select {
+			case <-release:
+				return 9, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := Go(context.Background(), func(context.Context) (int, error) {
+			return sf.Do(context.Background(), "key", fn)
+		})
+		time.Sleep(10 * time.Millisecond)
+
+		leaver := Go(context.Background(), func(context.Context) (int, error) {
+			return sf.Do(ctx, "key", fn)
+		})
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		_, err := leaver.Await(context.Background())
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		value, err := result.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 9, value)
+	})
+}
@@ -0,0 +1,188 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ThrottleOptions configures a throttler
+type ThrottleOptions struct {
+	Context  context.Context
+	Limit    int
+	Interval time.Duration
+}
+
+// Throttler limits the calls of a function to Limit calls per Interval (sliding window).
+// Triggers over the limit are coalesced into a single trailing call, made as soon as the window allows it.
+type Throttler struct {
+	fn       func()
+	limit    int
+	interval time.Duration
+
+	mu         sync.Mutex
+	calls      []time.Time
+	timer      *time.Timer
+	generation uint64 // Incremented on each (re)schedule, so stale timers are ignored
+	pending    bool
+	stopped    bool
+	stopCtx    func() bool
+}
+
+// NewThrottler creates a new throttler for fn (a non-positive limit means one call per interval).
+// The throttler is stopped when the context is cancelled.
+func NewThrottler(fn func(), opts ThrottleOptions) *Throttler {
+	// Allow a single call per interval if no limit is specified
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 1
+	}
+
+	// Create the throttler
+	t := &Throttler{
+		fn:       fn,
+		limit:    limit,
+		interval: opts.Interval,
+		calls:    make([]time.Time, 0, limit),
+	}
+
+	// Stop the throttler when the context is cancelled
+	if opts.Context != nil {
+		t.mu.Lock()
+		t.stopCtx = context.AfterFunc(opts.Context, t.Stop)
+		t.mu.Unlock()
+	}
+
+	// Return the throttler
+	return t
+}
+
+// Trigger calls the function immediately if the limit allows it (returns true),
+// otherwise it schedules a single trailing call (returns false)
+func (t *Throttler) Trigger() bool {
+	t.mu.Lock()
+
+	// Ignore triggers once stopped
+	if t.stopped {
+		t.mu.Unlock()
+		return false
+	}
+
+	// Call the function if the window has room
+	now := time.Now()
+	if t.acquire(now) {
+		t.mu.Unlock()
+		t.fn()
+		return true
+	}
+
+	// Schedule the trailing call
+	t.pending = true
+	if t.timer == nil {
+		t.schedule(now)
+	}
+	t.mu.Unlock()
+	return false
+}
+
+// Flush immediately calls the function if a trailing call is pending (the call counts towards the limit)
+func (t *Throttler) Flush() {
+	t.mu.Lock()
+	run := t.pending && !t.stopped
+	if run {
+		t.record(time.Now())
+	}
+	t.reset()
+	t.mu.Unlock()
+
+	if run {
+		t.fn()
+	}
+}
+
+// Stop discards any pending call and ignores all future triggers
+func (t *Throttler) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stopped = true
+	t.reset()
+
+	// Release the context watcher
+	if t.stopCtx != nil {
+		t.stopCtx()
+	}
+}
+
+// fire makes the trailing call if the window allows it, otherwise it reschedules
+func (t *Throttler) fire(generation uint64) {
+	t.mu.Lock()
+
+	// Ignore stale timers
+	if generation != t.generation || t.stopped || !t.pending {
+		t.mu.Unlock()
+		return
+	}
+
+	// Reschedule if the window is still full
+	now := time.Now()
+	if !t.acquire(now) {
+		t.schedule(now)
+		t.mu.Unlock()
+		return
+	}
+	t.reset()
+	t.mu.Unlock()
+
+	t.fn()
+}
+
+// acquire records a call if the window has room (must hold the lock)
+func (t *Throttler) acquire(now time.Time) bool {
+	t.prune(now)
+	if len(t.calls) >= t.limit {
+		return false
+	}
+	t.record(now)
+	return true
+}
+
+// record appends the call time to the window, dropping the oldest if full (must hold the lock)
+func (t *Throttler) record(now time.Time) {
+	if len(t.calls) >= t.limit {
+		t.calls = append(t.calls[:0], t.calls[1:]...)
+	}
+	t.calls = append(t.calls, now)
+}
+
+// prune drops the calls that left the window (must hold the lock)
+func (t *Throttler) prune(now time.Time) {
+	expired := 0
+	for expired < len(t.calls) && now.Sub(t.calls[expired]) >= t.interval {
+		expired++
+	}
+	if expired > 0 {
+		t.calls = append(t.calls[:0], t.calls[expired:]...)
+	}
+}
+
+// schedule starts the timer for when the oldest call leaves the window (must hold the lock)
+func (t *Throttler) schedule(now time.Time) {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	delay := t.calls[0].Add(t.interval).Sub(now)
+	t.generation++
+	generation := t.generation
+	t.timer = time.AfterFunc(delay, func() { t.fire(generation) })
+}
+
+// reset stops the timer and clears the pending call (must hold the lock)
+func (t *Throttler) reset() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.generation++
+	t.pending = false
+}
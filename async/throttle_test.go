@@ -0,0 +1,108 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottler(t *testing.T) {
+	t.Run("Allows up to the limit immediately", func(t *testing.T) {
+		var calls atomic.Int32
+		th := NewThrottler(func() { calls.Add(1) }, ThrottleOptions{Limit: 3, Interval: time.Hour})
+		defer th.Stop()
+
+		assert.True(t, th.Trigger())
+		assert.True(t, th.Trigger())
+		assert.True(t, th.Trigger())
+		assert.False(t, th.Trigger())
+		assert.False(t, th.Trigger())
+
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("Coalesces excess triggers into one trailing call", func(t *testing.T) {
+		var calls atomic.Int32
+		th := NewThrottler(func() { calls.Add(1) }, ThrottleOptions{Limit: 1, Interval: 30 * time.Millisecond})
+		defer th.Stop()
+
+		for range 5 {
+			th.Trigger()
+		}
+		assert.Equal(t, int32(1), calls.Load())
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Window slides", func(t *testing.T) {
+		th := NewThrottler(func() {}, ThrottleOptions{Limit: 2, Interval: 20 * time.Millisecond})
+		defer th.Stop()
+
+		assert.True(t, th.Trigger())
+		assert.True(t, th.Trigger())
+		th.Flush()
+
+		time.Sleep(30 * time.Millisecond)
+		assert.True(t, th.Trigger())
+	})
+
+	t.Run("Flush calls pending immediately", func(t *testing.T) {
+		var calls atomic.Int32
+		th := NewThrottler(func() { calls.Add(1) }, ThrottleOptions{Limit: 1, Interval: time.Hour})
+		defer th.Stop()
+
+		th.Trigger()
+		th.Trigger()
+		th.Flush()
+		assert.Equal(t, int32(2), calls.Load())
+
+		th.Flush()
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Stop discards pending call", func(t *testing.T) {
+		var calls atomic.Int32
+		th := NewThrottler(func() { calls.Add(1) }, ThrottleOptions{Limit: 1, Interval: 10 * time.Millisecond})
+
+		th.Trigger()
+		th.Trigger()
+		th.Stop()
+		time.Sleep(30 * time.Millisecond)
+
+		assert.False(t, th.Trigger())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Context cancellation stops the throttler", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		th := NewThrottler(func() {}, ThrottleOptions{Context: ctx, Limit: 10, Interval: time.Second})
+
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+
+		assert.False(t, th.Trigger())
+	})
+
+	t.Run("Concurrent triggers respect the limit", func(t *testing.T) {
+		var calls atomic.Int32
+		th := NewThrottler(func() { calls.Add(1) }, ThrottleOptions{Limit: 5, Interval: time.Hour})
+		defer th.Stop()
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				th.Trigger()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(5), calls.Load())
+	})
+}
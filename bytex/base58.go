@@ -0,0 +1,143 @@
+package bytex
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+// base58Alphabet is the Bitcoin alphabet (no 0, O, I, l)
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58DecodeMap maps from character to digit (0xFF for invalid characters)
+var base58DecodeMap = newDecodeMap(base58Alphabet)
+
+// Base58 is the Bitcoin base58 encoding, leading zero bytes are encoded as '1'
+// (the whole input is a single number, so encoding and decoding are quadratic, intended for IDs and hashes)
+var Base58 TextEncoding = base58Encoding{}
+
+// base58Encoding implements the base58 encoding
+type base58Encoding struct{}
+
+// AppendEncode appends the base58 encoding of src to dst
+func (base58Encoding) AppendEncode(dst, src []byte) []byte {
+	// Count the leading zeros (encoded as '1')
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+
+	// Reserve the space for the digits (log(256)/log(58) ~ 1.37 digits per byte)
+	size := (len(src)-zeros)*138/100 + 1
+	start := len(dst)
+	dst = slices.Grow(dst, zeros+size)[:start+zeros+size]
+	out := dst[start:]
+	digits := out[zeros:]
+	clear(digits)
+
+	// Convert the number from base 256 to base 58 (most significant digit first)
+	high := size - 1
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += int(digits[j]) << 8
+			digits[j] = byte(carry % 58)
+			carry /= 58
+		}
+		high = j
+	}
+
+	// Skip the leading zero digits
+	skip := 0
+	for skip < size && digits[skip] == 0 {
+		skip++
+	}
+
+	// Translate the digits to characters, moving them after the leading '1's
+	for i, digit := range digits[skip:] {
+		out[zeros+i] = base58Alphabet[digit]
+	}
+	for i := range zeros {
+		out[i] = '1'
+	}
+	return dst[:start+zeros+size-skip]
+}
+
+// AppendDecode appends the bytes decoded from the base58 src to dst
+func (base58Encoding) AppendDecode(dst, src []byte) ([]byte, error) {
+	// Count the leading '1's (decoded as zeros)
+	zeros := 0
+	for zeros < len(src) && src[zeros] == '1' {
+		zeros++
+	}
+
+	// Reserve the space for the bytes (log(58)/log(256) ~ 0.733 bytes per digit)
+	size := (len(src)-zeros)*733/1000 + 1
+	start := len(dst)
+	dst = slices.Grow(dst, zeros+size)[:start+zeros+size]
+	out := dst[start:]
+	value := out[zeros:]
+	clear(value)
+
+	// Convert the number from base 58 to base 256 (most significant byte first)
+	high := size - 1
+	for i, c := range src[zeros:] {
+		digit := base58DecodeMap[c]
+		if digit == 0xFF {
+			return dst[:start], fmt.Errorf("%w: %q at offset %d", ErrInvalidCharacter, c, zeros+i)
+		}
+		carry := int(digit)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += int(value[j]) * 58
+			value[j] = byte(carry)
+			carry >>= 8
+		}
+		high = j
+	}
+
+	// Skip the leading zero bytes of the number
+	skip := 0
+	for skip < size && value[skip] == 0 {
+		skip++
+	}
+
+	// Move the number after the leading zeros
+	copy(value, value[skip:])
+	clear(out[:zeros])
+	return dst[:start+zeros+size-skip], nil
+}
+
+// EncodeToString returns the base58 encoding of src
+func (e base58Encoding) EncodeToString(src []byte) string {
+	return stringsx.FromBytes(e.AppendEncode(nil, src))
+}
+
+// DecodeString returns the bytes decoded from the base58 string s
+func (e base58Encoding) DecodeString(s string) ([]byte, error) {
+	return e.AppendDecode(nil, stringsx.ToBytes(s))
+}
+
+// blocks returns zero sizes, base58 encodes the whole input at once
+func (base58Encoding) blocks() (int, int) {
+	return 0, 0
+}
+
+// ignored returns false, base58 does not ignore any character
+func (base58Encoding) ignored(byte) bool {
+	return false
+}
+
+// newDecodeMap creates a decode map for the alphabet (0xFF for invalid characters)
+func newDecodeMap(alphabet string) [256]byte {
+	var m [256]byte
+	for i := range m {
+		m[i] = 0xFF
+	}
+	for i := range len(alphabet) {
+		m[alphabet[i]] = byte(i)
+	}
+	return m
+}
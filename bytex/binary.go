@@ -0,0 +1,485 @@
+package bytex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+var (
+	ErrLimitExceeded  = errors.New("binary: size limit exceeded")
+	ErrVarintOverflow = errors.New("binary: varint overflows a 64-bit integer")
+	ErrInvalidLength  = errors.New("binary: invalid length")
+)
+
+// binaryReadChunk is the largest allocation made by BinaryReader.Bytes before the data arrives
+const binaryReadChunk = int(64 * KiB)
+
+// Prefix selects the encoding of the length of prefixed strings and byte slices
+type Prefix byte
+
+const (
+	PrefixU8      Prefix = iota // 1 byte length
+	PrefixU16                   // 2 bytes length (in the byte order of the reader/writer)
+	PrefixU32                   // 4 bytes length (in the byte order of the reader/writer)
+	PrefixUvarint               // Unsigned varint length
+)
+
+// BinaryOptions configures a BinaryReader or a BinaryWriter
+type BinaryOptions struct {
+	Order binary.ByteOrder // Byte order of multibyte values (default big endian)
+	Limit Size             // Maximum number of bytes read or written (zero for no limit)
+}
+
+// BinaryReader reads typed values from a stream.
+// Errors are sticky: after the first error every read returns zero values, and Err reports the error.
+type BinaryReader struct {
+	r     *bufio.Reader
+	order binary.ByteOrder
+	limit Size
+	pos   Size
+	err   error
+	buf   [8]byte
+}
+
+// NewBinaryReader creates a new binary reader (the reader is buffered, so it may read ahead of the position)
+func NewBinaryReader(r io.Reader, opts ...BinaryOptions) *BinaryReader {
+	// Resolve the options
+	var opt BinaryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Order == nil {
+		opt.Order = binary.BigEndian
+	}
+
+	// Reuse the buffered reader if possible
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	// Create the reader
+	return &BinaryReader{r: br, order: opt.Order, limit: opt.Limit}
+}
+
+// Err returns the first error encountered
+func (r *BinaryReader) Err() error {
+	return r.err
+}
+
+// Pos returns the number of bytes consumed so far
+func (r *BinaryReader) Pos() Size {
+	return r.pos
+}
+
+// SetOrder changes the byte order of the following reads (i.e. after reading a byte order mark)
+func (r *BinaryReader) SetOrder(order binary.ByteOrder) {
+	r.order = order
+}
+
+// U8 reads an unsigned 8-bit integer
+func (r *BinaryReader) U8() uint8 {
+	if b := r.read(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// U16 reads an unsigned 16-bit integer
+func (r *BinaryReader) U16() uint16 {
+	if b := r.read(2); b != nil {
+		return r.order.Uint16(b)
+	}
+	return 0
+}
+
+// U32 reads an unsigned 32-bit integer
+func (r *BinaryReader) U32() uint32 {
+	if b := r.read(4); b != nil {
+		return r.order.Uint32(b)
+	}
+	return 0
+}
+
+// U64 reads an unsigned 64-bit integer
+func (r *BinaryReader) U64() uint64 {
+	if b := r.read(8); b != nil {
+		return r.order.Uint64(b)
+	}
+	return 0
+}
+
+// I8 reads a signed 8-bit integer
+func (r *BinaryReader) I8() int8 {
+	return int8(r.U8())
+}
+
+// I16 reads a signed 16-bit integer
+func (r *BinaryReader) I16() int16 {
+	return int16(r.U16())
+}
+
+// I32 reads a signed 32-bit integer
+func (r *BinaryReader) I32() int32 {
+	return int32(r.U32())
+}
+
+// I64 reads a signed 64-bit integer
+func (r *BinaryReader) I64() int64 {
+	return int64(r.U64())
+}
+
+// F32 reads an IEEE 754 32-bit float
+func (r *BinaryReader) F32() float32 {
+	return math.Float32frombits(r.U32())
+}
+
+// F64 reads an IEEE 754 64-bit float
+func (r *BinaryReader) F64() float64 {
+	return math.Float64frombits(r.U64())
+}
+
+// Uvarint reads an unsigned varint (encoding/binary format)
+func (r *BinaryReader) Uvarint() uint64 {
+	var value uint64
+	for i := range binary.MaxVarintLen64 {
+		// Read the next byte
+		b := r.U8()
+		if r.err != nil {
+			return 0
+		}
+
+		// Stop at the last byte (the 10th byte may only hold the highest bit)
+		if b < 0x80 {
+			if i == binary.MaxVarintLen64-1 && b > 1 {
+				break
+			}
+			return value | uint64(b)<<(7*i)
+		}
+		value |= uint64(b&0x7f) << (7 * i)
+	}
+
+	// Record the overflow
+	r.fail(ErrVarintOverflow)
+	return 0
+}
+
+// Varint reads a signed, zig-zag encoded varint (encoding/binary format)
+func (r *BinaryReader) Varint() int64 {
+	ux := r.Uvarint()
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x
+}
+
+// Bytes reads exactly n bytes into a new slice
+func (r *BinaryReader) Bytes(n int) []byte {
+	// Validate the length before allocating
+	if !r.check(n) {
+		return nil
+	}
+
+	// Read the bytes in chunks, growing the slice as the data arrives (so a forged length cannot exhaust memory)
+	b := make([]byte, 0, min(n, binaryReadChunk))
+	for len(b) < n {
+		chunk := min(n-len(b), binaryReadChunk)
+		b = slices.Grow(b, chunk)
+		read, err := io.ReadFull(r.r, b[len(b):len(b)+chunk])
+		b = b[:len(b)+read]
+		if err != nil {
+			if err == io.EOF && len(b) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			r.fail(err)
+			return nil
+		}
+	}
+	r.pos += Size(n)
+	return b
+}
+
+// PrefixedBytes reads a length prefix followed by as many bytes
+func (r *BinaryReader) PrefixedBytes(prefix Prefix) []byte {
+	n := r.length(prefix)
+	if r.err != nil {
+		return nil
+	}
+	return r.Bytes(n)
+}
+
+// String reads a length prefix followed by as many bytes, as a string
+func (r *BinaryReader) String(prefix Prefix) string {
+	return string(r.PrefixedBytes(prefix))
+}
+
+// Peek returns the next n bytes without consuming them (the slice is only valid until the next read)
+func (r *BinaryReader) Peek(n int) ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.limit > 0 && r.pos+Size(n) > r.limit {
+		return nil, ErrLimitExceeded
+	}
+	return r.r.Peek(n)
+}
+
+// Skip discards the next n bytes
+func (r *BinaryReader) Skip(n int) {
+	// Validate the length
+	if !r.check(n) {
+		return
+	}
+
+	// Discard the bytes
+	discarded, err := r.r.Discard(n)
+	r.pos += Size(discarded)
+	if err != nil {
+		r.fail(err)
+	}
+}
+
+// read consumes n bytes (at most 8) into the scratch buffer, returning nil on error
+func (r *BinaryReader) read(n int) []byte {
+	// Validate the length
+	if !r.check(n) {
+		return nil
+	}
+
+	// Read the bytes
+	b := r.buf[:n]
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.fail(err)
+		return nil
+	}
+	r.pos += Size(n)
+	return b
+}
+
+// length reads a length prefix
+func (r *BinaryReader) length(prefix Prefix) int {
+	switch prefix {
+	case PrefixU8:
+		return int(r.U8())
+	case PrefixU16:
+		return int(r.U16())
+	case PrefixU32:
+		return int(r.U32())
+	case PrefixUvarint:
+		n := r.Uvarint()
+		if n > math.MaxInt32 {
+			r.fail(fmt.Errorf("%w: %d", ErrInvalidLength, n))
+			return 0
+		}
+		return int(n)
+	default:
+		r.fail(fmt.Errorf("%w: unknown prefix %d", ErrInvalidLength, prefix))
+		return 0
+	}
+}
+
+// check returns true if n more bytes can be read (recording an error otherwise)
+func (r *BinaryReader) check(n int) bool {
+	switch {
+	case r.err != nil:
+		return false
+	case n < 0:
+		r.fail(fmt.Errorf("%w: %d", ErrInvalidLength, n))
+		return false
+	case r.limit > 0 && r.pos+Size(n) > r.limit:
+		r.fail(fmt.Errorf("%w: %d bytes requested, %d remaining", ErrLimitExceeded, n, r.limit-r.pos))
+		return false
+	default:
+		return true
+	}
+}
+
+// fail records the first error, with the position it occurred at (io.EOF is kept as is at a value boundary)
+func (r *BinaryReader) fail(err error) {
+	if r.err != nil {
+		return
+	}
+	if err == io.EOF {
+		r.err = err
+		return
+	}
+	r.err = fmt.Errorf("binary: read at offset %d: %w", r.pos, err)
+}
+
+// BinaryWriter writes typed values to a stream.
+// Errors are sticky: after the first error every write is ignored, and Err reports the error.
+type BinaryWriter struct {
+	w     io.Writer
+	order binary.ByteOrder
+	limit Size
+	pos   Size
+	err   error
+	buf   [binary.MaxVarintLen64]byte
+}
+
+// NewBinaryWriter creates a new binary writer
+func NewBinaryWriter(w io.Writer, opts ...BinaryOptions) *BinaryWriter {
+	// Resolve the options
+	var opt BinaryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Order == nil {
+		opt.Order = binary.BigEndian
+	}
+
+	// Create the writer
+	return &BinaryWriter{w: w, order: opt.Order, limit: opt.Limit}
+}
+
+// Err returns the first error encountered
+func (w *BinaryWriter) Err() error {
+	return w.err
+}
+
+// Pos returns the number of bytes written so far
+func (w *BinaryWriter) Pos() Size {
+	return w.pos
+}
+
+// SetOrder changes the byte order of the following writes
+func (w *BinaryWriter) SetOrder(order binary.ByteOrder) {
+	w.order = order
+}
+
+// U8 writes an unsigned 8-bit integer
+func (w *BinaryWriter) U8(v uint8) {
+	w.buf[0] = v
+	w.Bytes(w.buf[:1])
+}
+
+// U16 writes an unsigned 16-bit integer
+func (w *BinaryWriter) U16(v uint16) {
+	w.order.PutUint16(w.buf[:2], v)
+	w.Bytes(w.buf[:2])
+}
+
+// U32 writes an unsigned 32-bit integer
+func (w *BinaryWriter) U32(v uint32) {
+	w.order.PutUint32(w.buf[:4], v)
+	w.Bytes(w.buf[:4])
+}
+
+// U64 writes an unsigned 64-bit integer
+func (w *BinaryWriter) U64(v uint64) {
+	w.order.PutUint64(w.buf[:8], v)
+	w.Bytes(w.buf[:8])
+}
+
+// I8 writes a signed 8-bit integer
+func (w *BinaryWriter) I8(v int8) {
+	w.U8(uint8(v))
+}
+
+// I16 writes a signed 16-bit integer
+func (w *BinaryWriter) I16(v int16) {
+	w.U16(uint16(v))
+}
+
+// I32 writes a signed 32-bit integer
+func (w *BinaryWriter) I32(v int32) {
+	w.U32(uint32(v))
+}
+
+// I64 writes a signed 64-bit integer
+func (w *BinaryWriter) I64(v int64) {
+	w.U64(uint64(v))
+}
+
+// F32 writes an IEEE 754 32-bit float
+func (w *BinaryWriter) F32(v float32) {
+	w.U32(math.Float32bits(v))
+}
+
+// F64 writes an IEEE 754 64-bit float
+func (w *BinaryWriter) F64(v float64) {
+	w.U64(math.Float64bits(v))
+}
+
+// Uvarint writes an unsigned varint (encoding/binary format)
+func (w *BinaryWriter) Uvarint(v uint64) {
+	n := binary.PutUvarint(w.buf[:], v)
+	w.Bytes(w.buf[:n])
+}
+
+// Varint writes a signed, zig-zag encoded varint (encoding/binary format)
+func (w *BinaryWriter) Varint(v int64) {
+	n := binary.PutVarint(w.buf[:], v)
+	w.Bytes(w.buf[:n])
+}
+
+// Bytes writes the bytes as they are
+func (w *BinaryWriter) Bytes(b []byte) {
+	// Validate the length
+	if w.err != nil {
+		return
+	}
+	if w.limit > 0 && w.pos+Size(len(b)) > w.limit {
+		w.fail(fmt.Errorf("%w: %d bytes written, %d remaining", ErrLimitExceeded, len(b), w.limit-w.pos))
+		return
+	}
+
+	// Write the bytes
+	n, err := w.w.Write(b)
+	w.pos += Size(n)
+	if err != nil {
+		w.fail(err)
+	}
+}
+
+// PrefixedBytes writes the length of the bytes as a prefix followed by the bytes
+func (w *BinaryWriter) PrefixedBytes(prefix Prefix, b []byte) {
+	// Write the length prefix, if it fits
+	n := uint64(len(b))
+	switch prefix {
+	case PrefixU8:
+		if n > math.MaxUint8 {
+			w.fail(fmt.Errorf("%w: %d does not fit a 1 byte prefix", ErrInvalidLength, n))
+			return
+		}
+		w.U8(uint8(n))
+	case PrefixU16:
+		if n > math.MaxUint16 {
+			w.fail(fmt.Errorf("%w: %d does not fit a 2 bytes prefix", ErrInvalidLength, n))
+			return
+		}
+		w.U16(uint16(n))
+	case PrefixU32:
+		if n > math.MaxUint32 {
+			w.fail(fmt.Errorf("%w: %d does not fit a 4 bytes prefix", ErrInvalidLength, n))
+			return
+		}
+		w.U32(uint32(n))
+	case PrefixUvarint:
+		w.Uvarint(n)
+	default:
+		w.fail(fmt.Errorf("%w: unknown prefix %d", ErrInvalidLength, prefix))
+		return
+	}
+
+	// Write the bytes
+	w.Bytes(b)
+}
+
+// String writes the length of the string as a prefix followed by the string
+func (w *BinaryWriter) String(prefix Prefix, s string) {
+	w.PrefixedBytes(prefix, []byte(s))
+}
+
+// fail records the first error, with the position it occurred at
+func (w *BinaryWriter) fail(err error) {
+	if w.err == nil {
+		w.err = fmt.Errorf("binary: write at offset %d: %w", w.pos, err)
+	}
+}
@@ -0,0 +1,203 @@
+package bytex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewBinaryWriter(&buf, BinaryOptions{Order: order})
+			w.U8(0xAB)
+			w.U16(0xBEEF)
+			w.U32(0xDEADBEEF)
+			w.U64(math.MaxUint64 - 1)
+			w.I8(-1)
+			w.I16(-300)
+			w.I32(-70000)
+			w.I64(math.MinInt64)
+			w.F32(1.5)
+			w.F64(math.Pi)
+			w.Uvarint(300)
+			w.Varint(-300)
+			w.String(PrefixU8, "IHDR")
+			w.String(PrefixU16, "hello")
+			w.String(PrefixU32, "")
+			w.PrefixedBytes(PrefixUvarint, []byte{1, 2, 3})
+			assert.NoError(t, w.Err())
+			assert.Equal(t, Size(buf.Len()), w.Pos())
+
+			r := NewBinaryReader(&buf, BinaryOptions{Order: order})
+			assert.Equal(t, uint8(0xAB), r.U8())
+			assert.Equal(t, uint16(0xBEEF), r.U16())
+			assert.Equal(t, uint32(0xDEADBEEF), r.U32())
+			assert.Equal(t, uint64(math.MaxUint64-1), r.U64())
+			assert.Equal(t, int8(-1), r.I8())
+			assert.Equal(t, int16(-300), r.I16())
+			assert.Equal(t, int32(-70000), r.I32())
+			assert.Equal(t, int64(math.MinInt64), r.I64())
+			assert.Equal(t, float32(1.5), r.F32())
+			assert.Equal(t, math.Pi, r.F64())
+			assert.Equal(t, uint64(300), r.Uvarint())
+			assert.Equal(t, int64(-300), r.Varint())
+			assert.Equal(t, "IHDR", r.String(PrefixU8))
+			assert.Equal(t, "hello", r.String(PrefixU16))
+			assert.Equal(t, "", r.String(PrefixU32))
+			assert.Equal(t, []byte{1, 2, 3}, r.PrefixedBytes(PrefixUvarint))
+			assert.NoError(t, r.Err())
+			assert.Equal(t, w.Pos(), r.Pos())
+
+			r.U8()
+			assert.ErrorIs(t, r.Err(), io.EOF)
+		})
+	}
+}
+
+func TestBinaryReader(t *testing.T) {
+	t.Run("Byte order", func(t *testing.T) {
+		data := []byte{0x01, 0x02, 0x01, 0x02}
+		r := NewBinaryReader(bytes.NewReader(data))
+		assert.Equal(t, uint16(0x0102), r.U16())
+		r.SetOrder(binary.LittleEndian)
+		assert.Equal(t, uint16(0x0201), r.U16())
+	})
+
+	t.Run("PNG chunk", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBinaryWriter(&buf)
+		w.Bytes([]byte("\x89PNG\r\n\x1a\n"))
+		w.U32(4)
+		w.Bytes([]byte("tEXt"))
+		w.Bytes([]byte("data"))
+		w.U32(0x12345678)
+
+		r := NewBinaryReader(&buf)
+		r.Skip(8)
+		length := r.U32()
+		chunkType, err := r.Peek(4)
+		assert.NoError(t, err)
+		assert.Equal(t, "tEXt", string(chunkType))
+		assert.Equal(t, Size(12), r.Pos())
+
+		assert.Equal(t, "tEXt", string(r.Bytes(4)))
+		assert.Equal(t, "data", string(r.Bytes(int(length))))
+		assert.Equal(t, uint32(0x12345678), r.U32())
+		assert.NoError(t, r.Err())
+	})
+
+	t.Run("Errors are sticky", func(t *testing.T) {
+		r := NewBinaryReader(bytes.NewReader([]byte{0x01, 0x02, 0x03}))
+		assert.Zero(t, r.U32())
+		assert.ErrorIs(t, r.Err(), io.ErrUnexpectedEOF)
+
+		err := r.Err()
+		assert.Zero(t, r.U8())
+		assert.Nil(t, r.Bytes(1))
+		assert.Equal(t, err, r.Err())
+	})
+
+	t.Run("Limit is enforced", func(t *testing.T) {
+		r := NewBinaryReader(bytes.NewReader(make([]byte, 64)), BinaryOptions{Limit: 10})
+		r.U64()
+		assert.NoError(t, r.Err())
+
+		_, err := r.Peek(4)
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+
+		r.U32()
+		assert.ErrorIs(t, r.Err(), ErrLimitExceeded)
+		assert.Equal(t, Size(8), r.Pos())
+	})
+
+	t.Run("Length prefix is checked before allocating", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBinaryWriter(&buf)
+		w.U32(math.MaxUint32)
+
+		r := NewBinaryReader(&buf, BinaryOptions{Limit: MiB})
+		assert.Nil(t, r.PrefixedBytes(PrefixU32))
+		assert.ErrorIs(t, r.Err(), ErrLimitExceeded)
+	})
+
+	t.Run("Forged length without limit allocates as data arrives", func(t *testing.T) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		r := NewBinaryReader(bytes.NewReader([]byte{0x7F, 0xFF, 0xFF, 0xFF}))
+		assert.Nil(t, r.PrefixedBytes(PrefixU32))
+		runtime.ReadMemStats(&after)
+
+		assert.ErrorIs(t, r.Err(), io.EOF)
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(MiB))
+	})
+
+	t.Run("Large reads span several chunks", func(t *testing.T) {
+		data := bytes.Repeat([]byte("0123456789"), 20000)
+		r := NewBinaryReader(bytes.NewReader(data))
+		assert.Equal(t, data, r.Bytes(len(data)))
+		assert.NoError(t, r.Err())
+
+		r = NewBinaryReader(bytes.NewReader(data))
+		assert.Nil(t, r.Bytes(len(data)+1))
+		assert.ErrorIs(t, r.Err(), io.ErrUnexpectedEOF)
+		assert.Zero(t, r.Pos())
+	})
+
+	t.Run("Varint overflow", func(t *testing.T) {
+		data := bytes.Repeat([]byte{0xFF}, 11)
+		r := NewBinaryReader(bytes.NewReader(data))
+		assert.Zero(t, r.Uvarint())
+		assert.ErrorIs(t, r.Err(), ErrVarintOverflow)
+	})
+
+	t.Run("Varint matches encoding/binary", func(t *testing.T) {
+		for _, v := range []int64{0, 1, -1, 63, -64, 1 << 40, math.MaxInt64, math.MinInt64} {
+			r := NewBinaryReader(bytes.NewReader(binary.AppendVarint(nil, v)))
+			assert.Equal(t, v, r.Varint())
+			assert.NoError(t, r.Err())
+		}
+	})
+}
+
+func TestBinaryWriter(t *testing.T) {
+	t.Run("Limit is enforced", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBinaryWriter(&buf, BinaryOptions{Limit: 6})
+		w.U32(1)
+		w.U32(2)
+		assert.ErrorIs(t, w.Err(), ErrLimitExceeded)
+		assert.Equal(t, 4, buf.Len())
+	})
+
+	t.Run("Prefix overflow", func(t *testing.T) {
+		w := NewBinaryWriter(io.Discard)
+		w.PrefixedBytes(PrefixU8, make([]byte, 256))
+		assert.ErrorIs(t, w.Err(), ErrInvalidLength)
+		assert.Zero(t, w.Pos())
+	})
+
+	t.Run("Write errors are sticky", func(t *testing.T) {
+		failing := errors.New("disk full")
+		w := NewBinaryWriter(failingWriter{failing})
+		w.U8(1)
+		w.U8(2)
+		assert.ErrorIs(t, w.Err(), failing)
+	})
+}
+
+// failingWriter is a writer that always fails
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
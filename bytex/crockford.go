@@ -0,0 +1,155 @@
+package bytex
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ" // Symbols for the values 0-31 (no I, L, O, U)
+	crockfordCheck    = "*~$=U"                            // Additional check symbols for the values 32-36
+)
+
+// crockfordDecodeMap maps from character to value (0xFF for invalid characters), case-insensitive with aliases
+var crockfordDecodeMap = func() [256]byte {
+	m := newDecodeMap(crockfordAlphabet + crockfordCheck)
+	for i := range len(crockfordAlphabet) {
+		c := crockfordAlphabet[i]
+		if c >= 'A' && c <= 'Z' {
+			m[c+'a'-'A'] = byte(i)
+		}
+	}
+	m['u'] = m['U']
+	m['O'], m['o'] = 0, 0
+	m['I'], m['i'], m['L'], m['l'] = 1, 1, 1, 1
+	return m
+}()
+
+var (
+	// Crockford32 is Crockford's base32 encoding (case-insensitive, hyphens are ignored when decoding)
+	Crockford32 TextEncoding = crockfordEncoding{}
+	// Crockford32Check is Crockford's base32 encoding followed by a check symbol
+	// (the bytes as a big-endian number modulo 37)
+	Crockford32Check TextEncoding = crockfordEncoding{checksum: true}
+)
+
+// crockfordEncoding implements Crockford's base32 encoding
+type crockfordEncoding struct {
+	checksum bool
+}
+
+// AppendEncode appends the base32 encoding of src to dst (5 bits per symbol, without padding)
+func (e crockfordEncoding) AppendEncode(dst, src []byte) []byte {
+	// Reserve the space for the symbols
+	dst = slices.Grow(dst, (len(src)*8+4)/5+1)
+
+	// Encode 5 bits at a time
+	var bits uint
+	var buffer uint16
+	for _, b := range src {
+		buffer = buffer<<8 | uint16(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			dst = append(dst, crockfordAlphabet[buffer>>bits&0x1F])
+		}
+	}
+
+	// Encode the remaining bits, padded with zeros
+	if bits > 0 {
+		dst = append(dst, crockfordAlphabet[buffer<<(5-bits)&0x1F])
+	}
+
+	// Append the check symbol if requested
+	if e.checksum {
+		dst = append(dst, (crockfordAlphabet + crockfordCheck)[crockfordMod37(src)])
+	}
+	return dst
+}
+
+// AppendDecode appends the bytes decoded from the base32 src to dst
+func (e crockfordEncoding) AppendDecode(dst, src []byte) ([]byte, error) {
+	start := len(dst)
+
+	// Separate the check symbol
+	var check byte
+	if e.checksum {
+		end := len(src) - 1
+		for end >= 0 && src[end] == '-' {
+			end--
+		}
+		if end < 0 {
+			return dst, fmt.Errorf("%w: missing check symbol", ErrInvalidTextSize)
+		}
+		check = crockfordDecodeMap[src[end]]
+		if check == 0xFF {
+			return dst, fmt.Errorf("%w: %q at offset %d", ErrInvalidCharacter, src[end], end)
+		}
+		src = src[:end]
+	}
+
+	// Decode 5 bits at a time
+	dst = slices.Grow(dst, len(src)*5/8)
+	var bits uint
+	var buffer uint16
+	for i, c := range src {
+		if c == '-' {
+			continue
+		}
+		value := crockfordDecodeMap[c]
+		if value >= 32 {
+			return dst[:start], fmt.Errorf("%w: %q at offset %d", ErrInvalidCharacter, c, i)
+		}
+		buffer = buffer<<5 | uint16(value)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			dst = append(dst, byte(buffer>>bits))
+		}
+	}
+
+	// Reject incomplete symbols (more than 4 remaining bits, or non-zero padding)
+	if bits >= 5 || buffer&(1<<bits-1) != 0 {
+		return dst[:start], fmt.Errorf("%w: trailing bits", ErrInvalidTextSize)
+	}
+
+	// Verify the check symbol
+	if e.checksum && crockfordMod37(dst[start:]) != check {
+		return dst[:start], ErrInvalidChecksum
+	}
+	return dst, nil
+}
+
+// EncodeToString returns the base32 encoding of src
+func (e crockfordEncoding) EncodeToString(src []byte) string {
+	return stringsx.FromBytes(e.AppendEncode(nil, src))
+}
+
+// DecodeString returns the bytes decoded from the base32 string s
+func (e crockfordEncoding) DecodeString(s string) ([]byte, error) {
+	return e.AppendDecode(nil, stringsx.ToBytes(s))
+}
+
+// blocks returns 5 bytes per 8 symbols, or zero sizes with a checksum (computed over the whole input)
+func (e crockfordEncoding) blocks() (int, int) {
+	if e.checksum {
+		return 0, 0
+	}
+	return 5, 8
+}
+
+// ignored returns true for hyphens (used to make long values readable)
+func (crockfordEncoding) ignored(c byte) bool {
+	return c == '-'
+}
+
+// crockfordMod37 returns the bytes as a big-endian number modulo 37
+func crockfordMod37(src []byte) byte {
+	mod := 0
+	for _, b := range src {
+		mod = (mod<<8 | int(b)) % 37
+	}
+	return byte(mod)
+}
@@ -0,0 +1,139 @@
+package bytex
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/r3dpixel/toolkit/sonicx"
+	"github.com/r3dpixel/toolkit/stringsx"
+	"github.com/r3dpixel/toolkit/symbols"
+)
+
+// MarshalStyle selects how a Size is written by the text and JSON marshalers
+type MarshalStyle byte
+
+const (
+	StyleString        MarshalStyle = iota // Exact, using the largest evenly dividing unit ("1536B", "2GiB")
+	StyleBytes                             // Exact, as a plain number of bytes (JSON number)
+	StyleHumanReadable                     // Approximate, using the largest unit with fractions ("1.50KiB")
+)
+
+// DefaultMarshalStyle is the style used when marshaling sizes (unmarshaling accepts all styles)
+var DefaultMarshalStyle = StyleString
+
+// MarshalText implements encoding.TextMarshaler using the DefaultMarshalStyle
+func (s Size) MarshalText() ([]byte, error) {
+	return s.AppendText(nil)
+}
+
+// AppendText implements encoding.TextAppender using the DefaultMarshalStyle
+func (s Size) AppendText(b []byte) ([]byte, error) {
+	switch DefaultMarshalStyle {
+	case StyleBytes:
+		return strconv.AppendInt(b, int64(s), 10), nil
+	case StyleHumanReadable:
+		return append(b, s.HumanReadable()...), nil
+	default:
+		return append(b, s.String()...), nil
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting plain numbers and size strings ("1024", "1.5GiB")
+func (s *Size) UnmarshalText(text []byte) error {
+	size, err := ParseSize(stringsx.FromBytes(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler using the DefaultMarshalStyle (StyleBytes produces a JSON number)
+func (s Size) MarshalJSON() ([]byte, error) {
+	// Write plain numbers
+	if DefaultMarshalStyle == StyleBytes {
+		return strconv.AppendInt(nil, int64(s), 10), nil
+	}
+
+	// Write quoted strings
+	text, err := s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return strconv.AppendQuote(make([]byte, 0, len(text)+2), stringsx.FromBytes(text)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both JSON numbers and strings ("1.5GiB")
+func (s *Size) UnmarshalJSON(data []byte) error {
+	// Ignore null values
+	if stringsx.FromBytes(data) == symbols.Null {
+		return nil
+	}
+
+	// Decode the JSON value
+	var value any
+	if err := sonicx.Config.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	// Parse the value according to its type
+	switch v := value.(type) {
+	case string:
+		return s.UnmarshalText(stringsx.ToBytes(v))
+	case float64:
+		// Prefer the raw integer text, so large integers do not lose precision
+		if n, err := strconv.ParseInt(stringsx.FromBytes(data), 10, 64); err == nil {
+			*s = Size(n)
+			return nil
+		}
+		return s.setFloat(v)
+	default:
+		return fmt.Errorf("invalid size: unsupported JSON value %s", data)
+	}
+}
+
+// Set implements flag.Value, parsing the size string
+func (s *Size) Set(value string) error {
+	return s.UnmarshalText(stringsx.ToBytes(value))
+}
+
+// Type returns the type name used in flag usage messages (pflag.Value compatible)
+func (s *Size) Type() string {
+	return "size"
+}
+
+// Scan implements sql.Scanner, accepting integer, float, text and NULL columns
+func (s *Size) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*s = 0
+	case int64:
+		*s = Size(v)
+	case float64:
+		return s.setFloat(v)
+	case []byte:
+		return s.UnmarshalText(v)
+	case string:
+		return s.UnmarshalText(stringsx.ToBytes(v))
+	default:
+		return fmt.Errorf("invalid size: cannot scan %T", src)
+	}
+	return nil
+}
+
+// setFloat sets the size from a float number of bytes (truncated), rejecting values outside the range of Size
+func (s *Size) setFloat(v float64) error {
+	// NaN fails both comparisons, and 2^63 is the first float64 above math.MaxInt64
+	if !(v >= math.MinInt64 && v < math.MaxInt64) {
+		return ErrSizeOverflow
+	}
+	*s = Size(v)
+	return nil
+}
+
+// Value implements driver.Valuer, storing the size as an integer number of bytes
+func (s Size) Value() (driver.Value, error) {
+	return int64(s), nil
+}
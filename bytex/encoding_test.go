@@ -0,0 +1,187 @@
+package bytex
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.TextMarshaler   = Size(0)
+	_ encoding.TextUnmarshaler = (*Size)(nil)
+	_ json.Marshaler           = Size(0)
+	_ json.Unmarshaler         = (*Size)(nil)
+	_ flag.Value               = (*Size)(nil)
+)
+
+// withMarshalStyle sets the DefaultMarshalStyle for the duration of the test
+func withMarshalStyle(t *testing.T, style MarshalStyle) {
+	t.Helper()
+	original := DefaultMarshalStyle
+	DefaultMarshalStyle = style
+	t.Cleanup(func() {
+		DefaultMarshalStyle = original
+	})
+}
+
+func TestSizeMarshalText(t *testing.T) {
+	tests := []struct {
+		name  string
+		style MarshalStyle
+		size  Size
+		want  string
+	}{
+		{"String style exact unit", StyleString, 2 * GiB, "2GiB"},
+		{"String style bytes", StyleString, 1536, "1536B"},
+		{"Bytes style", StyleBytes, 2 * KiB, "2048"},
+		{"Human readable style", StyleHumanReadable, 1536, "1.50KiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withMarshalStyle(t, tt.style)
+			got, err := tt.size.MarshalText()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestSizeUnmarshalText(t *testing.T) {
+	var s Size
+	assert.NoError(t, s.UnmarshalText([]byte("1.5GiB")))
+	assert.Equal(t, Size(1610612736), s)
+
+	assert.NoError(t, s.UnmarshalText([]byte("4096")))
+	assert.Equal(t, Size(4096), s)
+
+	assert.Error(t, s.UnmarshalText([]byte("12XB")))
+}
+
+func TestSizeJSON(t *testing.T) {
+	type config struct {
+		Limit Size  `json:"limit"`
+		Max   *Size `json:"max,omitempty"`
+	}
+
+	t.Run("Marshal string style", func(t *testing.T) {
+		withMarshalStyle(t, StyleString)
+		data, err := json.Marshal(config{Limit: 512 * MiB})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"limit":"512MiB"}`, string(data))
+	})
+
+	t.Run("Marshal bytes style", func(t *testing.T) {
+		withMarshalStyle(t, StyleBytes)
+		data, err := json.Marshal(config{Limit: 512 * MiB})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"limit":536870912}`, string(data))
+	})
+
+	t.Run("Unmarshal strings and numbers", func(t *testing.T) {
+		var c config
+		assert.NoError(t, json.Unmarshal([]byte(`{"limit":"1.5GiB","max":1048576}`), &c))
+		assert.Equal(t, Size(1610612736), c.Limit)
+		assert.Equal(t, MiB, *c.Max)
+	})
+
+	t.Run("Unmarshal large integers without precision loss", func(t *testing.T) {
+		var s Size
+		assert.NoError(t, json.Unmarshal([]byte(`9007199254740993`), &s))
+		assert.Equal(t, Size(9007199254740993), s)
+	})
+
+	t.Run("Unmarshal out of range floats", func(t *testing.T) {
+		for _, data := range []string{`1e30`, `-1e30`, `9223372036854775808`, `9.3e18`} {
+			s := KiB
+			assert.ErrorIs(t, json.Unmarshal([]byte(data), &s), ErrSizeOverflow, data)
+			assert.Equal(t, KiB, s, data)
+		}
+
+		var s Size
+		assert.NoError(t, json.Unmarshal([]byte(`1.5e3`), &s))
+		assert.Equal(t, Size(1500), s)
+	})
+
+	t.Run("Unmarshal null leaves value unchanged", func(t *testing.T) {
+		s := KiB
+		assert.NoError(t, json.Unmarshal([]byte(`null`), &s))
+		assert.Equal(t, KiB, s)
+	})
+
+	t.Run("Unmarshal invalid values", func(t *testing.T) {
+		var s Size
+		assert.Error(t, json.Unmarshal([]byte(`"lots"`), &s))
+		assert.Error(t, json.Unmarshal([]byte(`true`), &s))
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		for _, style := range []MarshalStyle{StyleString, StyleBytes} {
+			withMarshalStyle(t, style)
+			data, err := json.Marshal(3 * TiB)
+			assert.NoError(t, err)
+
+			var s Size
+			assert.NoError(t, json.Unmarshal(data, &s))
+			assert.Equal(t, 3*TiB, s)
+		}
+	})
+}
+
+func TestSizeFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	limit := 10 * MiB
+	fs.Var(&limit, "limit", "memory limit")
+
+	assert.NoError(t, fs.Parse([]string{"-limit", "2GiB"}))
+	assert.Equal(t, 2*GiB, limit)
+	assert.Error(t, fs.Parse([]string{"-limit", "huge"}))
+	assert.Equal(t, "size", limit.Type())
+}
+
+func TestSizeSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Size
+		wantErr bool
+	}{
+		{"Integer column", int64(2048), 2048, false},
+		{"Float column", float64(1024), 1024, false},
+		{"Text column", "1MiB", MiB, false},
+		{"Blob column", []byte("1KB"), KB, false},
+		{"NULL column", nil, 0, false},
+		{"Invalid text", "nope", 0, true},
+		{"Unsupported type", true, 0, true},
+		{"Float column overflow", float64(1e30), 0, true},
+		{"Float column negative overflow", float64(-1e30), 0, true},
+		{"Float column NaN", math.NaN(), 0, true},
+		{"Float column infinity", math.Inf(1), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Size
+			err := s.Scan(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if _, isFloat := tt.src.(float64); isFloat {
+					assert.ErrorIs(t, err, ErrSizeOverflow)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s)
+		})
+	}
+
+	value, err := (5 * GiB).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5*GiB), value)
+}
@@ -0,0 +1,157 @@
+package bytex
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnitSystem selects the family of units used when formatting
+type UnitSystem byte
+
+const (
+	Mixed UnitSystem = iota // Largest unit across both IEC and SI units (as HumanReadable)
+	IEC                     // Binary units, powers of 1024 (KiB, MiB, GiB, ...)
+	SI                      // Decimal units, powers of 1000 (kB, MB, GB, ...)
+)
+
+// NoDecimals is the FormatOptions.Precision that formats values without a fractional part
+const NoDecimals = -1
+
+// FormatOptions configures Size.FormatWith
+type FormatOptions struct {
+	System            UnitSystem // Family of units to choose from (default Mixed)
+	Precision         int        // Digits after the decimal separator (default 2, NoDecimals for none)
+	SignificantDigits int        // Round to this many significant digits instead of Precision (when positive)
+	Space             bool       // Separate the value from the unit with a space ("1.5 kB")
+	TrimZeros         bool       // Trim trailing zeros of the fractional part ("1.50" -> "1.5", "2.00" -> "2")
+	LongNames         bool       // Use long unit names with pluralization ("1 kilobyte", "1.5 kilobytes")
+	Locale            string     // Locale used to pick the decimal separator ("de", "fr-FR", "pt_BR.UTF-8")
+	DecimalSeparator  string     // Decimal separator, takes precedence over the locale (default ".")
+}
+
+// commaLanguages is the set of languages using a comma as decimal separator
+var commaLanguages = map[string]struct{}{
+	"bg": {}, "cs": {}, "da": {}, "de": {}, "el": {}, "es": {}, "et": {}, "fi": {}, "fr": {}, "hr": {},
+	"hu": {}, "id": {}, "it": {}, "lt": {}, "lv": {}, "nb": {}, "nl": {}, "nn": {}, "no": {}, "pl": {},
+	"pt": {}, "ro": {}, "ru": {}, "sk": {}, "sl": {}, "sr": {}, "sv": {}, "tr": {}, "uk": {}, "vi": {},
+}
+
+// DecimalSeparatorFor returns the decimal separator for the locale ("," for "de-DE", "." for "en-US" or unknown locales)
+func DecimalSeparatorFor(locale string) string {
+	// Extract the language from the locale
+	language, _, _ := strings.Cut(locale, "_")
+	language, _, _ = strings.Cut(language, "-")
+	language, _, _ = strings.Cut(language, ".")
+
+	// Look up the language
+	if _, ok := commaLanguages[strings.ToLower(language)]; ok {
+		return ","
+	}
+	return "."
+}
+
+// FormatWith returns the size formatted with the largest unit of the selected system that keeps the value >= 1
+func (s Size) FormatWith(opts FormatOptions) string {
+	// Select the candidate units, from largest to smallest
+	units := unitsOf(opts.System)
+
+	// Find the best unit (largest unit where value >= 1)
+	abs := math.Abs(float64(s))
+	index := len(units) - 1
+	for i, u := range units {
+		if abs >= float64(u.Size) {
+			index = i
+			break
+		}
+	}
+
+	// Format the value, moving to the next unit if rounding reaches it ("1024.00KiB" -> "1.00MiB")
+	number := formatNumber(float64(s)/float64(units[index].Size), opts)
+	if index > 0 {
+		rounded, _ := strconv.ParseFloat(number, 64)
+		if math.Abs(rounded)*float64(units[index].Size) >= float64(units[index-1].Size) {
+			index--
+			number = formatNumber(float64(s)/float64(units[index].Size), opts)
+		}
+	}
+
+	// Trim the trailing zeros if requested
+	if opts.TrimZeros && strings.Contains(number, ".") {
+		number = strings.TrimRight(strings.TrimRight(number, "0"), ".")
+	}
+
+	// Select the unit name
+	u := units[index]
+	name := u.Name
+	switch {
+	case opts.LongNames:
+		name = u.Long
+		if number != "1" && number != "-1" {
+			name += "s"
+		}
+	case opts.System == SI && u.Size == KB:
+		name = "kB"
+	}
+
+	// Replace the decimal separator
+	separator := opts.DecimalSeparator
+	if separator == "" {
+		separator = DecimalSeparatorFor(opts.Locale)
+	}
+	if separator != "." {
+		number = strings.Replace(number, ".", separator, 1)
+	}
+
+	// Join the value and the unit
+	if opts.Space || opts.LongNames {
+		return number + " " + name
+	}
+	return number + name
+}
+
+// unitsOf returns the units of the system, from largest to smallest (always ending with "B")
+func unitsOf(system UnitSystem) []unit {
+	if system == Mixed {
+		return unitList
+	}
+
+	// Keep the units of the system
+	units := make([]unit, 0, len(unitList)/2+1)
+	for _, u := range unitList {
+		if u.Size == B || u.isDecimal() == (system == SI) {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// formatNumber formats the value with the precision or significant digits of the options (always using ".")
+func formatNumber(value float64, opts FormatOptions) string {
+	// Use the fixed precision
+	if opts.SignificantDigits <= 0 {
+		precision := opts.Precision
+		switch {
+		case precision == 0:
+			precision = defaultPrecision
+		case precision < 0:
+			precision = 0
+		}
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	}
+
+	// Count the integer digits
+	if value == 0 {
+		return strconv.FormatFloat(0, 'f', opts.SignificantDigits-1, 64)
+	}
+	digits := int(math.Floor(math.Log10(math.Abs(value)))) + 1
+
+	// Round away the digits beyond the significant ones in the integer part ("1234" -> "1200")
+	if digits > opts.SignificantDigits {
+		scale := math.Pow10(digits - opts.SignificantDigits)
+		return strconv.FormatFloat(math.Round(value/scale)*scale, 'f', 0, 64)
+	}
+
+	// Keep the remaining significant digits in the fractional part
+	return strconv.FormatFloat(value, 'f', opts.SignificantDigits-digits, 64)
+}
@@ -0,0 +1,57 @@
+package bytex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeFormatWith(t *testing.T) {
+	tests := []struct {
+		name string
+		size Size
+		opts FormatOptions
+		want string
+	}{
+		{"Default matches HumanReadable", 1536, FormatOptions{}, "1.50KiB"},
+		{"Mixed picks the largest unit", 1500, FormatOptions{}, "1.46KiB"},
+		{"SI with space and trimming", 1500, FormatOptions{System: SI, Space: true, TrimZeros: true}, "1.5 kB"},
+		{"SI megabytes", 2500 * KB, FormatOptions{System: SI}, "2.50MB"},
+		{"IEC only", 1500, FormatOptions{System: IEC}, "1.46KiB"},
+		{"IEC skips SI units", 1500 * KB, FormatOptions{System: IEC, Space: true}, "1.43 MiB"},
+		{"Bytes", 512, FormatOptions{System: SI, Space: true, TrimZeros: true}, "512 B"},
+		{"Zero", 0, FormatOptions{TrimZeros: true}, "0B"},
+		{"Negative", -1536, FormatOptions{System: IEC, TrimZeros: true}, "-1.5KiB"},
+		{"Trim whole number", 2 * GiB, FormatOptions{TrimZeros: true}, "2GiB"},
+		{"Precision", 1536, FormatOptions{System: IEC, Precision: 3}, "1.500KiB"},
+		{"No decimals", 1536, FormatOptions{System: IEC, Precision: NoDecimals}, "2KiB"},
+		{"Rounding moves to the next unit", MiB - 1, FormatOptions{System: IEC}, "1.00MiB"},
+		{"Rounding moves to the next SI unit", 999_999, FormatOptions{System: SI, Precision: 1}, "1.0MB"},
+		{"Significant digits small", 1234, FormatOptions{System: SI, SignificantDigits: 3}, "1.23kB"},
+		{"Significant digits large", 123_456 * KB, FormatOptions{System: SI, SignificantDigits: 2}, "120MB"},
+		{"Significant digits zero", 0, FormatOptions{SignificantDigits: 3}, "0.00B"},
+		{"Long name singular", KiB, FormatOptions{System: IEC, LongNames: true, TrimZeros: true}, "1 kibibyte"},
+		{"Long name plural", 1500, FormatOptions{System: SI, LongNames: true, TrimZeros: true}, "1.5 kilobytes"},
+		{"Long name byte", 1, FormatOptions{LongNames: true, Precision: NoDecimals}, "1 byte"},
+		{"Long name bytes", 3, FormatOptions{LongNames: true, Precision: NoDecimals}, "3 bytes"},
+		{"Long name with decimals is plural", GB, FormatOptions{System: SI, LongNames: true}, "1.00 gigabytes"},
+		{"Locale decimal separator", 1536, FormatOptions{System: IEC, Space: true, Locale: "de-DE"}, "1,50 KiB"},
+		{"Locale with encoding", 1536, FormatOptions{System: IEC, Locale: "pt_BR.UTF-8"}, "1,50KiB"},
+		{"Explicit separator wins", 1536, FormatOptions{System: IEC, Locale: "fr", DecimalSeparator: "·"}, "1·50KiB"},
+		{"English locale", 1536, FormatOptions{System: IEC, Locale: "en-US"}, "1.50KiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.size.FormatWith(tt.opts))
+		})
+	}
+}
+
+func TestDecimalSeparatorFor(t *testing.T) {
+	assert.Equal(t, ",", DecimalSeparatorFor("de"))
+	assert.Equal(t, ",", DecimalSeparatorFor("FR_fr"))
+	assert.Equal(t, ".", DecimalSeparatorFor("en_GB.UTF-8"))
+	assert.Equal(t, ".", DecimalSeparatorFor("ja-JP"))
+	assert.Equal(t, ".", DecimalSeparatorFor(""))
+}
@@ -0,0 +1,175 @@
+package bytex
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+const (
+	hexDigits      = "0123456789abcdef"
+	hexDumpWidth   = 16 // Bytes per line
+	hexDumpLineLen = 79 // Length of a full line, including the newline
+)
+
+// hexDumpState formats the lines of a dump, squeezing repeated lines into a single '*'
+type hexDumpState struct {
+	offset    int
+	previous  [hexDumpWidth]byte
+	hasLine   bool
+	squeezing bool
+}
+
+// appendLine appends the line (at most 16 bytes) to dst
+func (s *hexDumpState) appendLine(dst, line []byte) []byte {
+	// Squeeze full lines repeating the previous one
+	full := len(line) == hexDumpWidth
+	if full && s.hasLine && bytes.Equal(line, s.previous[:]) {
+		if !s.squeezing {
+			dst = append(dst, '*', '\n')
+			s.squeezing = true
+		}
+		s.offset += len(line)
+		return dst
+	}
+	s.squeezing = false
+	if full {
+		copy(s.previous[:], line)
+		s.hasLine = true
+	}
+
+	// Append the offset
+	dst = appendHexOffset(dst, s.offset)
+	dst = append(dst, ' ', ' ')
+
+	// Append the bytes in hex, with an extra space between the two halves
+	for i := range hexDumpWidth {
+		if i < len(line) {
+			dst = append(dst, hexDigits[line[i]>>4], hexDigits[line[i]&0x0F], ' ')
+		} else {
+			dst = append(dst, ' ', ' ', ' ')
+		}
+		if i == hexDumpWidth/2-1 {
+			dst = append(dst, ' ')
+		}
+	}
+
+	// Append the printable characters
+	dst = append(dst, ' ', '|')
+	for _, b := range line {
+		if b < 0x20 || b > 0x7E {
+			b = '.'
+		}
+		dst = append(dst, b)
+	}
+	dst = append(dst, '|', '\n')
+
+	// Advance the offset
+	s.offset += len(line)
+	return dst
+}
+
+// appendEnd appends the final offset line (the total length), if anything was dumped
+func (s *hexDumpState) appendEnd(dst []byte) []byte {
+	if s.offset == 0 {
+		return dst
+	}
+	return append(appendHexOffset(dst, s.offset), '\n')
+}
+
+// appendHexOffset appends the offset as at least 8 lower case hex digits
+func appendHexOffset(dst []byte, offset int) []byte {
+	// Count the digits
+	digits := 8
+	for offset>>(4*digits) > 0 {
+		digits++
+	}
+
+	// Append the digits
+	for i := digits - 1; i >= 0; i-- {
+		dst = append(dst, hexDigits[offset>>(4*i)&0x0F])
+	}
+	return dst
+}
+
+// AppendHexDump appends the `hexdump -C` style dump of src to dst, ending with the total length
+func AppendHexDump(dst, src []byte) []byte {
+	var state hexDumpState
+	for len(src) > 0 {
+		n := min(len(src), hexDumpWidth)
+		dst = state.appendLine(dst, src[:n])
+		src = src[n:]
+	}
+	return state.appendEnd(dst)
+}
+
+// HexDump returns the `hexdump -C` style dump of src, ending with the total length
+func HexDump(src []byte) string {
+	lines := (len(src) + hexDumpWidth - 1) / hexDumpWidth
+	return stringsx.FromBytes(AppendHexDump(make([]byte, 0, (lines+1)*hexDumpLineLen), src))
+}
+
+// HexDumper is a writer dumping everything written to it in the `hexdump -C` style
+type HexDumper struct {
+	w       io.Writer
+	state   hexDumpState
+	pending []byte
+	out     []byte
+	err     error
+}
+
+// NewHexDumper creates a new hex dumper writing to w (Close must be called to dump the final line)
+func NewHexDumper(w io.Writer) *HexDumper {
+	return &HexDumper{w: w, pending: make([]byte, 0, hexDumpWidth)}
+}
+
+// Write dumps the complete lines of the data, buffering the rest
+func (d *HexDumper) Write(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	// Dump the lines
+	written := len(p)
+	d.out = d.out[:0]
+	for len(p) > 0 {
+		// Fill the pending line
+		n := min(len(p), hexDumpWidth-len(d.pending))
+		d.pending = append(d.pending, p[:n]...)
+		p = p[n:]
+
+		// Dump the line when complete
+		if len(d.pending) == hexDumpWidth {
+			d.out = d.state.appendLine(d.out, d.pending)
+			d.pending = d.pending[:0]
+		}
+	}
+
+	// Write the dumped lines
+	if len(d.out) > 0 {
+		_, d.err = d.w.Write(d.out)
+	}
+	return written, d.err
+}
+
+// Close dumps the final line and the total length (it does not close the underlying writer)
+func (d *HexDumper) Close() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	// Dump the final line
+	d.out = d.out[:0]
+	if len(d.pending) > 0 {
+		d.out = d.state.appendLine(d.out, d.pending)
+		d.pending = d.pending[:0]
+	}
+
+	// Dump the total length
+	d.out = d.state.appendEnd(d.out)
+	if len(d.out) > 0 {
+		_, d.err = d.w.Write(d.out)
+	}
+	return d.err
+}
@@ -0,0 +1,67 @@
+package bytex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexDump(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"Empty", nil, ""},
+		{
+			"Partial line",
+			[]byte("Hello, World!\n"),
+			"00000000  48 65 6c 6c 6f 2c 20 57  6f 72 6c 64 21 0a        |Hello, World!.|\n" +
+				"0000000e\n",
+		},
+		{
+			"Full line",
+			[]byte("0123456789abcdef"),
+			"00000000  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|\n" +
+				"00000010\n",
+		},
+		{
+			"Short line",
+			[]byte{0x00, 0x7f, 0x80},
+			"00000000  00 7f 80                                          |...|\n" +
+				"00000003\n",
+		},
+		{
+			"Repeated lines are squeezed",
+			append(make([]byte, 64), 'x'),
+			"00000000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+				"*\n" +
+				"00000040  78                                                |x|\n" +
+				"00000041\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HexDump(tt.input))
+			assert.Equal(t, "> "+tt.want, string(AppendHexDump([]byte("> "), tt.input)))
+		})
+	}
+}
+
+func TestHexDumper(t *testing.T) {
+	input := []byte(strings.Repeat("toolkit ", 10) + strings.Repeat("\x00", 40) + "end")
+
+	var out bytes.Buffer
+	d := NewHexDumper(&out)
+	for chunk := range slicesChunk(input, 5) {
+		n, err := d.Write(chunk)
+		assert.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	assert.NoError(t, d.Close())
+
+	assert.Equal(t, HexDump(input), out.String())
+}
@@ -0,0 +1,249 @@
+package bytex
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	minLimiterBurst = 512 * B                // Smallest default burst of a Limiter
+	burstWindow     = 100 * time.Millisecond // Default burst of a Limiter, as a fraction of its rate
+	unlimitedChunk  = 32 * KiB               // Chunk size of readers and writers that are not limited
+)
+
+// LimiterOptions configures a Limiter
+type LimiterOptions struct {
+	Burst Size // Largest amount of data transferred at once (default 100ms worth of the rate, at least 512B)
+}
+
+// Limiter is a token bucket limiting the throughput shared by any number of readers and writers.
+// A nil Limiter, or a Limiter with a zero rate, does not limit.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   Rate
+	burst  Size
+	fixed  bool
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a new limiter with the given rate (starts with a full bucket)
+func NewLimiter(rate Rate, opts ...LimiterOptions) *Limiter {
+	// Resolve the options
+	var opt LimiterOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	// Create the limiter
+	l := &Limiter{burst: opt.Burst, fixed: opt.Burst > 0, last: time.Now()}
+	l.setRate(rate)
+	l.tokens = float64(l.burst)
+	return l
+}
+
+// Rate returns the current rate of the limiter
+func (l *Limiter) Rate() Rate {
+	if l == nil {
+		return Rate{}
+	}
+
+	// Lock the limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Return the rate
+	return l.rate
+}
+
+// SetRate changes the rate of the limiter (takes effect for all streams, including pending waits)
+func (l *Limiter) SetRate(rate Rate) {
+	// A nil limiter does not limit
+	if l == nil {
+		return
+	}
+
+	// Lock the limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Accumulate the tokens at the old rate, then switch
+	l.advance(time.Now())
+	l.setRate(rate)
+	l.tokens = min(l.tokens, float64(l.burst))
+}
+
+// WaitN blocks until n bytes can be transferred, or the context is cancelled
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for n > 0 {
+		// Take at most one burst at a time
+		chunk := min(n, l.chunkSize())
+
+		// Reserve the chunk
+		wait := l.reserve(chunk)
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				l.refund(chunk)
+				return ctx.Err()
+			}
+		}
+
+		// Continue with the remaining bytes
+		n -= chunk
+	}
+	return nil
+}
+
+// Reader returns a reader limited by the limiter (the context cancels pending waits)
+func (l *Limiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &limitedReader{ctx: ctx, r: r, l: l}
+}
+
+// Writer returns a writer limited by the limiter (the context cancels pending waits)
+func (l *Limiter) Writer(ctx context.Context, w io.Writer) io.Writer {
+	// Use a background context if none is provided
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &limitedWriter{ctx: ctx, w: w, l: l}
+}
+
+// chunkSize returns the largest amount of data that can be reserved at once
+func (l *Limiter) chunkSize() int {
+	if l == nil {
+		return int(unlimitedChunk)
+	}
+
+	// Lock the limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Return the burst (or a regular chunk when not limited)
+	if l.rate.IsZero() {
+		return int(unlimitedChunk)
+	}
+	return int(l.burst)
+}
+
+// reserve takes n tokens from the bucket, returning how long to wait before they are available
+func (l *Limiter) reserve(n int) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	// Lock the limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Do not limit with a zero rate
+	if l.rate.IsZero() {
+		return 0
+	}
+
+	// Take the tokens (the bucket may go into debt)
+	l.advance(time.Now())
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	// Return the time needed to pay the debt
+	return time.Duration(-l.tokens / l.rate.BytesPerSecond() * float64(time.Second))
+}
+
+// refund returns n tokens to the bucket (after a cancelled wait)
+func (l *Limiter) refund(n int) {
+	// Lock the limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Return the tokens
+	l.tokens = min(l.tokens+float64(n), float64(l.burst))
+}
+
+// advance fills the bucket with the tokens accumulated since the last update (must be called with the lock held)
+func (l *Limiter) advance(now time.Time) {
+	if !l.rate.IsZero() {
+		elapsed := now.Sub(l.last).Seconds()
+		l.tokens = min(l.tokens+elapsed*l.rate.BytesPerSecond(), float64(l.burst))
+	}
+	l.last = now
+}
+
+// setRate sets the rate, and the default burst derived from it (must be called with the lock held)
+func (l *Limiter) setRate(rate Rate) {
+	l.rate = rate
+	if !l.fixed {
+		l.burst = max(Size(rate.BytesPerSecond()*burstWindow.Seconds()), minLimiterBurst)
+	}
+}
+
+// limitedReader is a reader limited by a Limiter
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+// Read reads at most one burst, waiting for the bytes read
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// Stop early if the context is cancelled
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	// Read at most one burst
+	if chunk := r.l.chunkSize(); len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := r.r.Read(p)
+
+	// Wait for the bytes read
+	if waitErr := r.l.WaitN(r.ctx, n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// limitedWriter is a writer limited by a Limiter
+type limitedWriter struct {
+	ctx context.Context
+	w   io.Writer
+	l   *Limiter
+}
+
+// Write writes the data one burst at a time, waiting before each burst
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// Wait for the next burst
+		chunk := min(len(p), w.l.chunkSize())
+		if err := w.l.WaitN(w.ctx, chunk); err != nil {
+			return written, err
+		}
+
+		// Write the burst
+		n, err := w.w.Write(p[:chunk])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[chunk:]
+	}
+	return written, nil
+}
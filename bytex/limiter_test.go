@@ -0,0 +1,111 @@
+package bytex
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("Reader is throttled", func(t *testing.T) {
+		l := NewLimiter(PerSecond(100*KiB), LimiterOptions{Burst: 10 * KiB})
+		src := bytes.NewReader(make([]byte, 30*KiB))
+
+		start := time.Now()
+		n, err := io.Copy(io.Discard, l.Reader(context.Background(), src))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(30*KiB), n)
+
+		// 20KiB over the initial burst at 100KiB/s
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	})
+
+	t.Run("Writer is throttled", func(t *testing.T) {
+		l := NewLimiter(PerSecond(100*KiB), LimiterOptions{Burst: 10 * KiB})
+		var dst bytes.Buffer
+
+		start := time.Now()
+		n, err := l.Writer(context.Background(), &dst).Write(make([]byte, 30*KiB))
+		assert.NoError(t, err)
+		assert.Equal(t, int(30*KiB), n)
+		assert.Equal(t, int(30*KiB), dst.Len())
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	})
+
+	t.Run("Rate is shared across streams", func(t *testing.T) {
+		l := NewLimiter(PerSecond(100*KiB), LimiterOptions{Burst: 10 * KiB})
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := io.Copy(io.Discard, l.Reader(context.Background(), bytes.NewReader(make([]byte, 10*KiB))))
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	})
+
+	t.Run("Context cancellation aborts the wait", func(t *testing.T) {
+		l := NewLimiter(PerSecond(KiB), LimiterOptions{Burst: KiB})
+		assert.NoError(t, l.WaitN(context.Background(), int(KiB)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		assert.ErrorIs(t, l.WaitN(ctx, int(KiB)), context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("Zero rate and nil limiter do not limit", func(t *testing.T) {
+		var nilLimiter *Limiter
+		for _, l := range []*Limiter{NewLimiter(Rate{}), nilLimiter} {
+			start := time.Now()
+			n, err := io.Copy(io.Discard, l.Reader(context.Background(), bytes.NewReader(make([]byte, MiB))))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(MiB), n)
+			assert.Less(t, time.Since(start), 100*time.Millisecond)
+		}
+		assert.True(t, nilLimiter.Rate().IsZero())
+		assert.NotPanics(t, func() { nilLimiter.SetRate(PerSecond(KiB)) })
+	})
+
+	t.Run("Nil context uses the background context", func(t *testing.T) {
+		l := NewLimiter(PerSecond(10 * MiB))
+		var ctx context.Context
+
+		n, err := io.Copy(io.Discard, l.Reader(ctx, bytes.NewReader(make([]byte, KiB))))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(KiB), n)
+
+		var buf bytes.Buffer
+		_, err = l.Writer(ctx, &buf).Write(make([]byte, KiB))
+		assert.NoError(t, err)
+		assert.Equal(t, int(KiB), buf.Len())
+
+		assert.NoError(t, l.WaitN(ctx, int(2*MiB)))
+	})
+
+	t.Run("SetRate changes the throughput", func(t *testing.T) {
+		l := NewLimiter(PerSecond(KiB))
+		assert.Equal(t, minLimiterBurst, l.burst)
+
+		l.SetRate(PerSecond(10 * MiB))
+		assert.Equal(t, PerSecond(10*MiB), l.Rate())
+		assert.Equal(t, MiB, l.burst)
+
+		start := time.Now()
+		assert.NoError(t, l.WaitN(context.Background(), int(2*MiB)))
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
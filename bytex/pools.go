@@ -1,10 +1,281 @@
 package bytex
 
-import "sync"
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
 
 // Buffer32k pool to reduce memory pressure when handling large amount of file operations (32KB buffers)
+//
+// Deprecated: use Buffers.Get(32 * KiB) and Buffers.Put instead, which do not box the slice on every Put
 var Buffer32k = sync.Pool{
 	New: func() any {
 		return make([]byte, 32*KiB)
 	},
 }
+
+const (
+	defaultPoolMinSize = 64 * B     // Smallest size class of the default pool
+	defaultPoolMaxSize = 64 * MiB   // Largest size class of the default pool
+	poisonByte         = byte(0xDE) // Pattern written over buffers put back in debug mode
+)
+
+// Buffers is the default buffer pool, shared across the toolkit
+var Buffers = NewBufferPool()
+
+// PoolOptions configures a BufferPool
+type PoolOptions struct {
+	MinSize Size // Smallest size class, rounded up to a power of two (default 64B)
+	MaxSize Size // Largest size class, rounded up to a power of two (default 64MiB), larger buffers are not pooled
+	Debug   bool // Detect double puts and writes after put (slow, buffers are never released to the GC)
+}
+
+// PoolStats is a snapshot of the BufferPool counters
+type PoolStats struct {
+	Hits     uint64 // Gets served from the pool
+	Misses   uint64 // Gets that had to allocate
+	Puts     uint64 // Buffers returned to the pool
+	Drops    uint64 // Buffers rejected by the pool (too small or too large)
+	Retained Size   // Bytes put back and not taken yet (an upper bound, the GC may free pooled buffers)
+}
+
+// BufferPool is a family of byte slice pools with power-of-two size classes
+type BufferPool struct {
+	minShift int
+	classes  []sync.Pool
+	holders  sync.Pool
+	buffers  sync.Pool
+
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	puts     atomic.Uint64
+	drops    atomic.Uint64
+	retained atomic.Int64
+
+	debug  bool
+	mu     sync.Mutex
+	free   [][][]byte
+	pooled map[*byte]struct{}
+}
+
+// NewBufferPool creates a new buffer pool with the given options
+func NewBufferPool(opts ...PoolOptions) *BufferPool {
+	// Resolve the options
+	var opt PoolOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.MinSize <= 0 {
+		opt.MinSize = defaultPoolMinSize
+	}
+	if opt.MaxSize <= 0 {
+		opt.MaxSize = defaultPoolMaxSize
+	}
+	opt.MaxSize = max(opt.MaxSize, opt.MinSize)
+
+	// Compute the size classes (powers of two between the bounds)
+	minShift := ceilShift(opt.MinSize)
+	maxShift := ceilShift(opt.MaxSize)
+	count := maxShift - minShift + 1
+
+	// Create the pool
+	p := &BufferPool{
+		minShift: minShift,
+		classes:  make([]sync.Pool, count),
+		debug:    opt.Debug,
+	}
+	if p.debug {
+		p.free = make([][][]byte, count)
+		p.pooled = make(map[*byte]struct{})
+	}
+	return p
+}
+
+// Get returns a buffer of length n with undefined contents, and a capacity of at least n (the size class of n)
+func (p *BufferPool) Get(n int) []byte {
+	n = max(n, 0)
+
+	// Allocate buffers larger than the largest class directly
+	class := p.classOf(n)
+	if class >= len(p.classes) {
+		p.misses.Add(1)
+		return make([]byte, n)
+	}
+
+	// Take a buffer from the class
+	if buf, ok := p.take(class); ok {
+		p.hits.Add(1)
+		p.retained.Add(-int64(p.classSize(class)))
+		return buf[:n]
+	}
+
+	// Allocate a new buffer with the capacity of the class
+	p.misses.Add(1)
+	return make([]byte, n, p.classSize(class))
+}
+
+// Put returns the buffer to the pool (the buffer must not be used afterward)
+func (p *BufferPool) Put(buf []byte) {
+	// Find the largest class the buffer can serve
+	class := p.floorClassOf(cap(buf))
+	if class < 0 || class >= len(p.classes) {
+		p.drops.Add(1)
+		return
+	}
+
+	// Return the buffer to the class
+	p.store(class, buf[:cap(buf)])
+	p.puts.Add(1)
+	p.retained.Add(int64(p.classSize(class)))
+}
+
+// GetBuffer returns an empty bytes.Buffer backed by a pooled slice of capacity of at least n
+func (p *BufferPool) GetBuffer(n int) *bytes.Buffer {
+	// Reuse a buffer header if available
+	b, _ := p.buffers.Get().(*bytes.Buffer)
+	if b == nil {
+		b = new(bytes.Buffer)
+	}
+
+	// Back the buffer with a pooled slice
+	*b = *bytes.NewBuffer(p.Get(n)[:0])
+	return b
+}
+
+// PutBuffer returns the bytes.Buffer and its backing slice to the pool (the buffer must not be used afterward)
+func (p *BufferPool) PutBuffer(b *bytes.Buffer) {
+	// Recover the whole backing slice
+	b.Reset()
+	p.Put(b.Bytes())
+
+	// Release the backing slice and reuse the header
+	*b = bytes.Buffer{}
+	p.buffers.Put(b)
+}
+
+// Stats returns a snapshot of the pool counters
+func (p *BufferPool) Stats() PoolStats {
+	return PoolStats{
+		Hits:     p.hits.Load(),
+		Misses:   p.misses.Load(),
+		Puts:     p.puts.Load(),
+		Drops:    p.drops.Load(),
+		Retained: Size(p.retained.Load()),
+	}
+}
+
+// take removes a buffer from the class (full capacity)
+func (p *BufferPool) take(class int) ([]byte, bool) {
+	if p.debug {
+		return p.takeDebug(class)
+	}
+
+	// Get the holder from the class
+	holder, _ := p.classes[class].Get().(*[]byte)
+	if holder == nil {
+		return nil, false
+	}
+
+	// Unwrap the buffer and recycle the holder
+	buf := *holder
+	*holder = nil
+	p.holders.Put(holder)
+	return buf, true
+}
+
+// store adds a buffer to the class (full capacity)
+func (p *BufferPool) store(class int, buf []byte) {
+	if p.debug {
+		p.storeDebug(class, buf)
+		return
+	}
+
+	// Wrap the buffer in a recycled holder (avoids boxing the slice header on every put)
+	holder, _ := p.holders.Get().(*[]byte)
+	if holder == nil {
+		holder = new([]byte)
+	}
+	*holder = buf
+
+	// Add the holder to the class
+	p.classes[class].Put(holder)
+}
+
+// takeDebug removes a buffer from the class free list, verifying it was not written to after being put
+func (p *BufferPool) takeDebug(class int) ([]byte, bool) {
+	// Lock the free lists
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Pop the last buffer of the class
+	list := p.free[class]
+	if len(list) == 0 {
+		return nil, false
+	}
+	buf := list[len(list)-1]
+	p.free[class] = list[:len(list)-1]
+	delete(p.pooled, unsafe.SliceData(buf))
+
+	// Verify the poison pattern is intact
+	for i, b := range buf {
+		if b != poisonByte {
+			panic(fmt.Sprintf("bytex: buffer %p was written at offset %d after being put", unsafe.SliceData(buf), i))
+		}
+	}
+	return buf, true
+}
+
+// storeDebug adds a buffer to the class free list, verifying it is not already pooled
+func (p *BufferPool) storeDebug(class int, buf []byte) {
+	// Lock the free lists
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Reject buffers that are already pooled
+	ptr := unsafe.SliceData(buf)
+	if _, ok := p.pooled[ptr]; ok {
+		panic(fmt.Sprintf("bytex: buffer %p was put twice", ptr))
+	}
+	p.pooled[ptr] = struct{}{}
+
+	// Poison the buffer, so later writes can be detected
+	for i := range buf {
+		buf[i] = poisonByte
+	}
+
+	// Push the buffer to the class
+	p.free[class] = append(p.free[class], buf)
+}
+
+// classOf returns the smallest class that fits n bytes
+func (p *BufferPool) classOf(n int) int {
+	if n <= 1<<p.minShift {
+		return 0
+	}
+	return bits.Len(uint(n-1)) - p.minShift
+}
+
+// floorClassOf returns the largest class a buffer of capacity c can serve (negative if none)
+func (p *BufferPool) floorClassOf(c int) int {
+	if c <= 0 {
+		return -1
+	}
+	return bits.Len(uint(c)) - 1 - p.minShift
+}
+
+// classSize returns the size of the class
+func (p *BufferPool) classSize(class int) Size {
+	return Size(1) << (p.minShift + class)
+}
+
+// ceilShift returns the exponent of the smallest power of two greater than or equal to s
+func ceilShift(s Size) int {
+	if s <= 1 {
+		return 0
+	}
+	return bits.Len64(uint64(s - 1))
+}
@@ -0,0 +1,139 @@
+package bytex
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferPool(t *testing.T) {
+	t.Run("Get rounds capacity up to the size class", func(t *testing.T) {
+		p := NewBufferPool()
+
+		tests := []struct {
+			n       int
+			wantCap int
+		}{
+			{0, 64},
+			{1, 64},
+			{64, 64},
+			{65, 128},
+			{1000, 1024},
+			{int(32 * KiB), int(32 * KiB)},
+			{int(3 * MiB), int(4 * MiB)},
+		}
+		for _, tt := range tests {
+			buf := p.Get(tt.n)
+			assert.Len(t, buf, tt.n)
+			assert.Equal(t, tt.wantCap, cap(buf))
+		}
+	})
+
+	t.Run("Put buffers are reused", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{Debug: true})
+
+		buf := p.Get(100)
+		p.Put(buf)
+		assert.Equal(t, 128*B, p.Stats().Retained)
+
+		again := p.Get(120)
+		assert.Equal(t, &buf[:1][0], &again[:1][0])
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(1), stats.Puts)
+		assert.Zero(t, stats.Retained)
+	})
+
+	t.Run("Foreign buffers serve the class below their capacity", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{Debug: true})
+
+		p.Put(make([]byte, 0, 1000))
+		assert.Equal(t, 512*B, p.Stats().Retained)
+
+		buf := p.Get(512)
+		assert.Len(t, buf, 512)
+		assert.Equal(t, 1000, cap(buf))
+	})
+
+	t.Run("Out of range buffers are not pooled", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{MinSize: KiB, MaxSize: MiB})
+
+		big := p.Get(int(2 * MiB))
+		assert.Len(t, big, int(2*MiB))
+
+		p.Put(big)
+		p.Put(make([]byte, 10))
+		p.Put(nil)
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(3), stats.Drops)
+		assert.Zero(t, stats.Puts)
+		assert.Zero(t, stats.Retained)
+	})
+
+	t.Run("Sizes are rounded to powers of two", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{MinSize: 100, MaxSize: 1000})
+		assert.Equal(t, 128, cap(p.Get(1)))
+		assert.Equal(t, 1024, cap(p.Get(1000)))
+		assert.Equal(t, 1025, cap(p.Get(1025)))
+	})
+
+	t.Run("Pooled bytes.Buffer", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{Debug: true})
+
+		b := p.GetBuffer(int(KiB))
+		assert.Zero(t, b.Len())
+		assert.Equal(t, int(KiB), b.Cap())
+
+		b.WriteString("hello")
+		assert.Equal(t, "hello", b.String())
+		p.PutBuffer(b)
+		assert.Equal(t, KiB, p.Stats().Retained)
+
+		b = p.GetBuffer(10)
+		assert.Zero(t, b.Len())
+		p.PutBuffer(b)
+	})
+
+	t.Run("Debug mode detects double put", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{Debug: true})
+
+		buf := p.Get(10)
+		p.Put(buf)
+		assert.Panics(t, func() { p.Put(buf) })
+	})
+
+	t.Run("Debug mode detects use after put", func(t *testing.T) {
+		p := NewBufferPool(PoolOptions{Debug: true})
+
+		buf := p.Get(10)
+		p.Put(buf)
+		buf[3] = 1
+		assert.Panics(t, func() { p.Get(10) })
+	})
+
+	t.Run("Concurrent use", func(t *testing.T) {
+		p := NewBufferPool()
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 100 {
+					buf := p.Get((i + 1) * (j + 1) * 10)
+					buf[len(buf)-1] = byte(j)
+					p.Put(buf)
+				}
+			}()
+		}
+		wg.Wait()
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(2000), stats.Hits+stats.Misses)
+		assert.Equal(t, uint64(2000), stats.Puts)
+	})
+}
@@ -0,0 +1,237 @@
+package bytex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProgressInterval  = 500 * time.Millisecond // Default interval between progress reports
+	defaultProgressSmoothing = 0.3                    // Default weight of the latest speed sample
+)
+
+// Progress is a snapshot of a transfer
+type Progress struct {
+	Total       Size          // Expected size of the transfer (zero when unknown)
+	Transferred Size          // Bytes transferred so far
+	Speed       Rate          // Smoothed speed (per second)
+	ETA         time.Duration // Estimated time remaining (zero when unknown)
+	Elapsed     time.Duration // Time since the transfer started
+	Done        bool          // True for the final report
+}
+
+// Percent returns the completed percentage (zero when the total is unknown)
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Transferred)/float64(p.Total)*100, 100)
+}
+
+// String returns the progress using human-readable sizes ("1.50MiB / 10.00MiB (15.0%) at 2.00MiB/s, ETA 4s")
+func (p Progress) String() string {
+	var sb strings.Builder
+
+	// Write the transferred bytes and the total if known
+	sb.WriteString(p.Transferred.HumanReadable())
+	if p.Total > 0 {
+		fmt.Fprintf(&sb, " / %s (%.1f%%)", p.Total.HumanReadable(), p.Percent())
+	}
+
+	// Write the speed
+	sb.WriteString(" at ")
+	sb.WriteString(p.Speed.HumanReadable())
+
+	// Write the ETA if known
+	if p.ETA > 0 {
+		sb.WriteString(", ETA ")
+		sb.WriteString(p.ETA.Round(time.Second).String())
+	}
+	return sb.String()
+}
+
+// ProgressFunc is called with the progress of a transfer
+type ProgressFunc func(Progress)
+
+// ProgressOptions configures a ProgressReader or a ProgressWriter
+type ProgressOptions struct {
+	Total      Size          // Expected size of the transfer (zero when unknown)
+	Interval   time.Duration // Minimum interval between reports (default 500ms)
+	Smoothing  float64       // Weight of the latest speed sample, between 0 and 1 (default 0.3)
+	OnProgress ProgressFunc  // Called from the goroutine performing the transfer
+}
+
+// progressTracker counts the transferred bytes and reports the progress at the configured interval
+type progressTracker struct {
+	mu       sync.Mutex
+	opts     ProgressOptions
+	start    time.Time
+	last     time.Time
+	lastSize Size
+	current  Progress
+}
+
+// newProgressTracker creates a new tracker, starting the clock
+func newProgressTracker(opts ProgressOptions) *progressTracker {
+	// Set default values if needed
+	if opts.Interval <= 0 {
+		opts.Interval = defaultProgressInterval
+	}
+	if opts.Smoothing <= 0 || opts.Smoothing > 1 {
+		opts.Smoothing = defaultProgressSmoothing
+	}
+
+	// Create the tracker
+	now := time.Now()
+	return &progressTracker{
+		opts:    opts,
+		start:   now,
+		last:    now,
+		current: Progress{Total: opts.Total, Speed: PerSecond(0)},
+	}
+}
+
+// add counts n transferred bytes, reporting the progress if the interval elapsed
+func (t *progressTracker) add(n int) {
+	// Lock the tracker
+	t.mu.Lock()
+
+	// Count the bytes
+	t.current.Transferred += Size(n)
+
+	// Skip the report until the interval elapses
+	now := time.Now()
+	if t.current.Done || now.Sub(t.last) < t.opts.Interval {
+		t.mu.Unlock()
+		return
+	}
+
+	// Update the snapshot
+	t.update(now)
+	progress := t.current
+	t.mu.Unlock()
+
+	// Report the progress (outside the lock)
+	t.report(progress)
+}
+
+// finish reports the final progress (only once)
+func (t *progressTracker) finish() {
+	// Lock the tracker
+	t.mu.Lock()
+	if t.current.Done {
+		t.mu.Unlock()
+		return
+	}
+
+	// Update the snapshot as final
+	t.update(time.Now())
+	t.current.Done = true
+	t.current.ETA = 0
+	progress := t.current
+	t.mu.Unlock()
+
+	// Report the progress (outside the lock)
+	t.report(progress)
+}
+
+// snapshot returns the current progress
+func (t *progressTracker) snapshot() Progress {
+	// Lock the tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Return the progress
+	return t.current
+}
+
+// update recomputes the speed, ETA and elapsed time (must be called with the lock held)
+func (t *progressTracker) update(now time.Time) {
+	// Sample the speed since the last update
+	if dt := now.Sub(t.last).Seconds(); dt > 0 {
+		sample := float64(t.current.Transferred-t.lastSize) / dt
+		speed := sample
+		if t.last != t.start {
+			speed = t.opts.Smoothing*sample + (1-t.opts.Smoothing)*t.current.Speed.BytesPerSecond()
+		}
+		t.current.Speed = PerSecond(Size(speed))
+	}
+	t.last = now
+	t.lastSize = t.current.Transferred
+
+	// Compute the elapsed time and the ETA
+	t.current.Elapsed = now.Sub(t.start)
+	t.current.ETA = 0
+	if remaining := t.current.Total - t.current.Transferred; remaining > 0 && t.current.Speed.Size > 0 {
+		t.current.ETA = time.Duration(float64(remaining) / t.current.Speed.BytesPerSecond() * float64(time.Second))
+	}
+}
+
+// report calls the callback if set
+func (t *progressTracker) report(progress Progress) {
+	if t.opts.OnProgress != nil {
+		t.opts.OnProgress(progress)
+	}
+}
+
+// ProgressReader is a reader reporting the progress of the bytes read
+type ProgressReader struct {
+	r       io.Reader
+	tracker *progressTracker
+}
+
+// NewProgressReader creates a new progress reader (the final report is sent on EOF)
+func NewProgressReader(r io.Reader, opts ProgressOptions) *ProgressReader {
+	return &ProgressReader{r: r, tracker: newProgressTracker(opts)}
+}
+
+// Read reads from the underlying reader, counting the bytes read
+func (r *ProgressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.tracker.add(n)
+	if err == io.EOF {
+		r.tracker.finish()
+	}
+	return n, err
+}
+
+// Progress returns the current progress (safe for concurrent use)
+func (r *ProgressReader) Progress() Progress {
+	return r.tracker.snapshot()
+}
+
+// Finish sends the final report, if not already sent (i.e. when the transfer stops before EOF)
+func (r *ProgressReader) Finish() {
+	r.tracker.finish()
+}
+
+// ProgressWriter is a writer reporting the progress of the bytes written
+type ProgressWriter struct {
+	w       io.Writer
+	tracker *progressTracker
+}
+
+// NewProgressWriter creates a new progress writer (Finish must be called to send the final report)
+func NewProgressWriter(w io.Writer, opts ProgressOptions) *ProgressWriter {
+	return &ProgressWriter{w: w, tracker: newProgressTracker(opts)}
+}
+
+// Write writes to the underlying writer, counting the bytes written
+func (w *ProgressWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.tracker.add(n)
+	return n, err
+}
+
+// Progress returns the current progress (safe for concurrent use)
+func (w *ProgressWriter) Progress() Progress {
+	return w.tracker.snapshot()
+}
+
+// Finish sends the final report, if not already sent
+func (w *ProgressWriter) Finish() {
+	w.tracker.finish()
+}
@@ -0,0 +1,137 @@
+package bytex
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowReader is a reader that sleeps before every read
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.r.Read(p)
+}
+
+func TestProgressReader(t *testing.T) {
+	t.Run("Reports periodically and once at EOF", func(t *testing.T) {
+		var mu sync.Mutex
+		var reports []Progress
+		src := &slowReader{r: iotest.OneByteReader(bytes.NewReader(make([]byte, 10))), delay: 5 * time.Millisecond}
+		r := NewProgressReader(src, ProgressOptions{
+			Total:    10,
+			Interval: 10 * time.Millisecond,
+			OnProgress: func(p Progress) {
+				mu.Lock()
+				reports = append(reports, p)
+				mu.Unlock()
+			},
+		})
+
+		n, err := io.Copy(io.Discard, r)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), n)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Greater(t, len(reports), 1)
+		for i := 1; i < len(reports); i++ {
+			assert.GreaterOrEqual(t, reports[i].Transferred, reports[i-1].Transferred)
+		}
+
+		last := reports[len(reports)-1]
+		assert.True(t, last.Done)
+		assert.Equal(t, Size(10), last.Transferred)
+		assert.Equal(t, 100.0, last.Percent())
+		assert.Zero(t, last.ETA)
+		assert.Positive(t, last.Speed.Size)
+		assert.Positive(t, last.Elapsed)
+	})
+
+	t.Run("Estimates the remaining time", func(t *testing.T) {
+		var first Progress
+		src := &slowReader{r: bytes.NewReader(make([]byte, KiB)), delay: 20 * time.Millisecond}
+		r := NewProgressReader(src, ProgressOptions{
+			Total:    10 * KiB,
+			Interval: time.Millisecond,
+			OnProgress: func(p Progress) {
+				if first.Transferred == 0 {
+					first = p
+				}
+			},
+		})
+
+		buf := make([]byte, 512)
+		_, err := r.Read(buf)
+		assert.NoError(t, err)
+		_, err = r.Read(buf)
+		assert.NoError(t, err)
+
+		assert.False(t, first.Done)
+		assert.Positive(t, first.ETA)
+		assert.Equal(t, r.Progress().Total, 10*KiB)
+	})
+
+	t.Run("Finish reports only once", func(t *testing.T) {
+		calls := 0
+		r := NewProgressReader(strings.NewReader("data"), ProgressOptions{OnProgress: func(Progress) { calls++ }})
+
+		_, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		r.Finish()
+		assert.Equal(t, 1, calls)
+		assert.True(t, r.Progress().Done)
+	})
+}
+
+func TestProgressWriter(t *testing.T) {
+	var last Progress
+	var dst bytes.Buffer
+	w := NewProgressWriter(&dst, ProgressOptions{OnProgress: func(p Progress) { last = p }})
+
+	_, err := w.Write(make([]byte, 3*KiB))
+	assert.NoError(t, err)
+	assert.Equal(t, 3*KiB, w.Progress().Transferred)
+	assert.False(t, last.Done)
+
+	w.Finish()
+	assert.True(t, last.Done)
+	assert.Equal(t, 3*KiB, last.Transferred)
+	assert.Zero(t, last.Total)
+	assert.Zero(t, last.Percent())
+}
+
+func TestProgressString(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress Progress
+		want     string
+	}{
+		{
+			"Known total",
+			Progress{Total: 10 * MiB, Transferred: 1536 * KiB, Speed: PerSecond(2 * MiB), ETA: 4200 * time.Millisecond},
+			"1.50MiB / 10.00MiB (15.0%) at 2.00MiB/s, ETA 4s",
+		},
+		{
+			"Unknown total",
+			Progress{Transferred: 512 * B, Speed: PerSecond(KiB)},
+			"512.00B at 1.00KiB/s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.progress.String())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package bytex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+// Rate represents a throughput, as an amount of data per period ("10MiB/s", "500KB/min")
+type Rate struct {
+	Size Size          // Amount of data per period
+	Per  time.Duration // Length of the period (one second when zero)
+}
+
+// period represents a named rate period
+type period struct {
+	Per   time.Duration
+	Names []string
+}
+
+// periodList is the list of named periods, the first name is used for formatting
+var periodList = []period{
+	{time.Millisecond, []string{"ms", "msec", "millisecond"}},
+	{time.Second, []string{"s", "sec", "second"}},
+	{time.Minute, []string{"min", "m", "minute"}},
+	{time.Hour, []string{"h", "hr", "hour"}},
+	{24 * time.Hour, []string{"d", "day"}},
+}
+
+// periodsByName maps from lower case name (singular and plural, except single letters) to period
+var periodsByName = make(map[string]time.Duration)
+
+func init() {
+	for _, p := range periodList {
+		for _, name := range p.Names {
+			periodsByName[name] = p.Per
+			if len(name) > 1 {
+				periodsByName[name+"s"] = p.Per
+			}
+		}
+	}
+}
+
+// PerSecond returns a rate of the given size per second
+func PerSecond(s Size) Rate {
+	return Rate{Size: s, Per: time.Second}
+}
+
+// ParseRate parses a rate string ("10MiB/s", "500KB/min", "1GB/30s"), a bare size is a rate per second
+func ParseRate(s string) (Rate, error) {
+	// Split the size from the period
+	sizeText, periodText, hasPeriod := strings.Cut(s, "/")
+
+	// Parse the size
+	size, err := ParseSize(sizeText)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+	if size < 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: negative size", s)
+	}
+
+	// Default to a rate per second
+	if !hasPeriod {
+		return PerSecond(size), nil
+	}
+
+	// Parse the period by name, falling back to a duration ("30s", "1m30s")
+	periodText = strings.TrimSpace(periodText)
+	per, ok := periodsByName[strings.ToLower(periodText)]
+	if !ok {
+		if per, err = time.ParseDuration(periodText); err != nil || per <= 0 {
+			return Rate{}, fmt.Errorf("invalid rate %q: unknown period %q", s, periodText)
+		}
+	}
+
+	// Return the rate
+	return Rate{Size: size, Per: per}, nil
+}
+
+// IsZero returns true if the rate carries no data (an unlimited Limiter)
+func (r Rate) IsZero() bool {
+	return r.Size <= 0
+}
+
+// BytesPerSecond returns the rate in bytes per second
+func (r Rate) BytesPerSecond() float64 {
+	return float64(r.Size) / r.period().Seconds()
+}
+
+// String returns the rate using the exact size and the period name ("10MiB/s", "1GB/30s")
+func (r Rate) String() string {
+	// Use the period name if available
+	per := r.period()
+	for _, p := range periodList {
+		if p.Per == per {
+			return r.Size.String() + "/" + p.Names[0]
+		}
+	}
+
+	// Fall back to the duration of the period
+	return r.Size.String() + "/" + per.String()
+}
+
+// HumanReadable returns the rate per second using the largest unit with fractions ("1.50MiB/s")
+func (r Rate) HumanReadable() string {
+	return Size(r.BytesPerSecond()).HumanReadable() + "/s"
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting rate strings and plain sizes per second
+func (r *Rate) UnmarshalText(text []byte) error {
+	rate, err := ParseRate(stringsx.FromBytes(text))
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+// Set implements flag.Value, parsing the rate string
+func (r *Rate) Set(value string) error {
+	return r.UnmarshalText(stringsx.ToBytes(value))
+}
+
+// Type returns the type name used in flag usage messages (pflag.Value compatible)
+func (r *Rate) Type() string {
+	return "rate"
+}
+
+// period returns the period of the rate, defaulting to one second
+func (r Rate) period() time.Duration {
+	if r.Per <= 0 {
+		return time.Second
+	}
+	return r.Per
+}
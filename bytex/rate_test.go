@@ -0,0 +1,74 @@
+package bytex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Rate
+		wantErr bool
+	}{
+		{"Per second", "10MiB/s", Rate{10 * MiB, time.Second}, false},
+		{"Per minute", "500KB/min", Rate{500 * KB, time.Minute}, false},
+		{"Per hour long name", "1GB/hour", Rate{GB, time.Hour}, false},
+		{"Plural long name", "1GB/seconds", Rate{GB, time.Second}, false},
+		{"Milliseconds", "1KiB/ms", Rate{KiB, time.Millisecond}, false},
+		{"Single letter minute", "1MB/m", Rate{MB, time.Minute}, false},
+		{"Case insensitive", "1MB/Sec", Rate{MB, time.Second}, false},
+		{"Duration period", "1GB/30s", Rate{GB, 30 * time.Second}, false},
+		{"Bare size per second", "2MiB", Rate{2 * MiB, time.Second}, false},
+		{"Spaces", "1.5 MiB / s", Rate{1536 * KiB, time.Second}, false},
+		{"Unknown period", "1MB/week", Rate{}, true},
+		{"Zero period", "1MB/0s", Rate{}, true},
+		{"Negative size", "-1MB/s", Rate{}, true},
+		{"Invalid size", "lots/s", Rate{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRate(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRateString(t *testing.T) {
+	assert.Equal(t, "10MiB/s", PerSecond(10*MiB).String())
+	assert.Equal(t, "500KB/min", Rate{500 * KB, time.Minute}.String())
+	assert.Equal(t, "1GB/30s", Rate{GB, 30 * time.Second}.String())
+	assert.Equal(t, "1KiB/s", Rate{Size: KiB}.String())
+	assert.Equal(t, "1.00KiB/s", Rate{60 * KiB, time.Minute}.HumanReadable())
+	assert.InDelta(t, 1024.0, Rate{60 * KiB, time.Minute}.BytesPerSecond(), 1e-9)
+	assert.True(t, Rate{}.IsZero())
+}
+
+func TestRateText(t *testing.T) {
+	type config struct {
+		Limit Rate `json:"limit"`
+	}
+
+	data, err := json.Marshal(config{Limit: Rate{500 * KB, time.Minute}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"limit":"500KB/min"}`, string(data))
+
+	var c config
+	assert.NoError(t, json.Unmarshal([]byte(`{"limit":"10MiB/s"}`), &c))
+	assert.Equal(t, PerSecond(10*MiB), c.Limit)
+
+	var r Rate
+	assert.NoError(t, r.Set("1GB/h"))
+	assert.Equal(t, Rate{GB, time.Hour}, r)
+	assert.Equal(t, "rate", r.Type())
+}
@@ -1,9 +1,11 @@
 package bytex
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"math/big"
 	"strings"
+	"unicode"
 
 	"github.com/r3dpixel/toolkit/stringsx"
 )
@@ -31,39 +33,50 @@ const (
 	defaultPrecision = 2
 )
 
-// unit represents a byte size unit with its value and name
+// ErrSizeOverflow is returned when a parsed size does not fit in a Size
+var ErrSizeOverflow = errors.New("invalid size: value overflows the representable range")
+
+// unit represents a byte size unit with its value, name and long name
 type unit struct {
 	Size Size
 	Name string
+	Long string
+}
+
+// isDecimal returns true for SI units (powers of 1000), false for "B" and IEC units (powers of 1024)
+func (u unit) isDecimal() bool {
+	return u.Size > B && u.Size%KB == 0
 }
 
 // unitList is the ordered list of units from largest to smallest
 var unitList = []unit{
-	{EiB, "EiB"},
-	{EB, "EB"},
-	{PiB, "PiB"},
-	{PB, "PB"},
-	{TiB, "TiB"},
-	{TB, "TB"},
-	{GiB, "GiB"},
-	{GB, "GB"},
-	{MiB, "MiB"},
-	{MB, "MB"},
-	{KiB, "KiB"},
-	{KB, "KB"},
-	{B, "B"},
+	{EiB, "EiB", "exbibyte"},
+	{EB, "EB", "exabyte"},
+	{PiB, "PiB", "pebibyte"},
+	{PB, "PB", "petabyte"},
+	{TiB, "TiB", "tebibyte"},
+	{TB, "TB", "terabyte"},
+	{GiB, "GiB", "gibibyte"},
+	{GB, "GB", "gigabyte"},
+	{MiB, "MiB", "mebibyte"},
+	{MB, "MB", "megabyte"},
+	{KiB, "KiB", "kibibyte"},
+	{KB, "KB", "kilobyte"},
+	{B, "B", "byte"},
 }
 
 // unitsBySize maps from Size to unit
 var unitsBySize = make(map[Size]unit)
 
-// unitsByName maps from string name to unit
+// unitsByName maps from string name (short, long and plural long, upper case) to unit
 var unitsByName = make(map[string]unit)
 
 func init() {
 	for _, u := range unitList {
 		unitsBySize[u.Size] = u
 		unitsByName[strings.ToUpper(u.Name)] = u
+		unitsByName[strings.ToUpper(u.Long)] = u
+		unitsByName[strings.ToUpper(u.Long)+"S"] = u
 	}
 }
 
@@ -133,53 +146,115 @@ func (s Size) BytesString() string {
 	return fmt.Sprintf("%dB", s)
 }
 
+// ParseOptions configures size parsing
+type ParseOptions struct {
+	// IECOnly rejects the SI (decimal) units "KB", "MB", ..., "EB", accepting only "B" and the IEC (binary) units
+	IECOnly bool
+}
+
 // ParseSize parses a size string. A size string is a possibly signed sequence of
-// decimal numbers, each with optional fraction and a unit suffix, such as "300KB", "1.5GiB" or "2.5MB".
-// Valid size units are "B", "KB", "KiB", "MB", "MiB", "GB", "GiB", "TB", "TiB", "PB", "PiB", "EB", "EiB".
-func ParseSize(s string) (Size, error) {
+// decimal numbers, each with optional fraction and a unit suffix, such as "300KB", "1.5GiB" or "1GiB 512MiB".
+// Valid size units are "B", "KB", "KiB", "MB", "MiB", "GB", "GiB", "TB", "TiB", "PB", "PiB", "EB", "EiB",
+// and their long names ("bytes", "kilobyte", "kibibytes", ...), case-insensitive. A single number without a unit is in bytes,
+// while compound sizes require a unit on every term.
+// The value is computed exactly (fractional bytes are truncated at the end), and overflowing values are rejected.
+func ParseSize(s string, opts ...ParseOptions) (Size, error) {
 	if stringsx.IsBlank(s) {
 		return 0, nil
 	}
 
+	// Get the options
+	var options ParseOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
 	// Extract sign
-	sign := 1
+	s = strings.TrimSpace(s)
+	negative := false
 	switch s[0] {
 	case '+':
 		s = s[1:]
-		sign = 1
 	case '-':
 		s = s[1:]
-		sign = -1
+		negative = true
 	}
 
-	// Find where number ends
-	i := strings.IndexFunc(s, func(r rune) bool {
-		return r != '.' && (r < '0' || r > '9')
-	})
-	if i == -1 {
-		i = len(s)
+	// Sum all terms exactly
+	total := new(big.Rat)
+	term := new(big.Rat)
+	terms, bareTerm := 0, false
+	for ; ; terms++ {
+		// Skip the whitespace between terms (at least one term is required)
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if len(s) == 0 && terms > 0 {
+			break
+		}
+
+		// Find where number ends
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return r != '.' && (r < '0' || r > '9')
+		})
+		if i == -1 {
+			i = len(s)
+		}
+		number := s[:i]
+		if strings.Trim(number, ".") == "" {
+			return 0, fmt.Errorf("invalid size: missing number")
+		}
+		if strings.Count(number, ".") > 1 {
+			return 0, fmt.Errorf("invalid size: malformed number %q", number)
+		}
+		if _, ok := term.SetString(number); !ok {
+			return 0, fmt.Errorf("invalid size: malformed number %q", number)
+		}
+		s = strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+
+		// Find where unit ends
+		j := strings.IndexFunc(s, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		if j == -1 {
+			j = len(s)
+		}
+
+		// Parse unit
+		multiplier := B
+		if j > 0 {
+			u, ok := unitsByName[strings.ToUpper(s[:j])]
+			if !ok {
+				return 0, fmt.Errorf("invalid size: unknown unit %q", s[:j])
+			}
+			if options.IECOnly && u.isDecimal() {
+				return 0, fmt.Errorf("invalid size: SI unit %q not allowed, use IEC units", s[:j])
+			}
+			multiplier = u.Size
+		} else {
+			bareTerm = true
+		}
+		s = s[j:]
+
+		// Add the term to the total
+		total.Add(total, term.Mul(term, new(big.Rat).SetInt64(int64(multiplier))))
 	}
-	if i == 0 {
-		return 0, fmt.Errorf("invalid size: missing number")
+
+	// Compound sizes require a unit on every term (i.e. "1 2" is a typo rather than 3 bytes)
+	if terms > 1 && bareTerm {
+		return 0, fmt.Errorf("invalid size: missing unit in compound size")
 	}
 
-	value, err := strconv.ParseFloat(s[:i], 64)
-	if err != nil {
-		return 0, fmt.Errorf("invalid size: %v", err)
+	// Apply the sign
+	if negative {
+		total.Neg(total)
 	}
 
-	// Parse unit
-	unit := strings.ToUpper(strings.TrimSpace(s[i:]))
-	multiplier := B
-	if stringsx.IsNotBlank(unit) {
-		u, ok := unitsByName[unit]
-		if !ok {
-			return 0, fmt.Errorf("invalid size: unknown unit %q", unit)
-		}
-		multiplier = u.Size
+	// Truncate the fractional bytes and check the value fits
+	value := new(big.Int).Quo(total.Num(), total.Denom())
+	if !value.IsInt64() {
+		return 0, ErrSizeOverflow
 	}
 
-	return Size(value * float64(multiplier) * float64(sign)), nil
+	return Size(value.Int64()), nil
 }
 
 // Bytes returns the size as a floating-point number of bytes.
@@ -1,6 +1,7 @@
 package bytex
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -145,6 +146,115 @@ func TestParseSize(t *testing.T) {
 	}
 }
 
+func TestParseSizeExact(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Size
+		wantErr error
+	}{
+		{"Large fraction without rounding", "7.999999999999999999EiB", 9223372036854775806, nil},
+		{"Maximum value", "9223372036854775807", 9223372036854775807, nil},
+		{"Minimum value", "-8EiB", -9223372036854775808, nil},
+		{"Overflow by fraction", "8.000000000000000001EiB", 0, ErrSizeOverflow},
+		{"Overflow by one byte", "9223372036854775808", 0, ErrSizeOverflow},
+		{"Overflow huge", "100000EB", 0, ErrSizeOverflow},
+		{"Negative overflow", "-8EiB 1B", 0, ErrSizeOverflow},
+		{"Fraction truncated", "1.9999B", 1, nil},
+		{"Fraction summed before truncation", "0.5B 0.5B", 1, nil},
+		{"Precise decimal", "0.1KB", 100, nil},
+		{"Leading dot", ".5KiB", 512, nil},
+		{"Trailing dot", "2.KiB", 2048, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParseSize(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseSize(%q) unexpected error = %v", tt.input, err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSizeCompound(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Size
+		wantErr bool
+	}{
+		{"Two terms", "1GiB 512MiB", GiB + 512*MiB, false},
+		{"Without spaces", "1GiB512MiB", GiB + 512*MiB, false},
+		{"Trailing bytes", "1KiB 24B", KiB + 24, false},
+		{"Trailing bare number rejected", "1KiB 24", 0, true},
+		{"Leading bare number rejected", "24 1KiB", 0, true},
+		{"Bare numbers rejected", "1 2", 0, true},
+		{"Negative compound", "-1MiB 1KiB", -(MiB + KiB), false},
+		{"Mixed SI and IEC", "1MB 1MiB", MB + MiB, false},
+		{"Long names", "2 megabytes 3 kibibyte", 2*MB + 3*KiB, false},
+		{"Long singular byte", "1 byte", 1, false},
+		{"Long plural bytes", "10 bytes", 10, false},
+		{"Long names case insensitive", "1 GiBiByTe", GiB, false},
+		{"Exbibytes long", "1 exbibyte", EiB, false},
+		{"Unit without number", "1GiB MiB", 0, true},
+		{"Unknown long unit", "1 gigabit", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSizeIECOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Size
+		wantErr bool
+	}{
+		{"IEC unit", "1.5GiB", GiB + 512*MiB, false},
+		{"Bytes", "100B", 100, false},
+		{"No unit", "100", 100, false},
+		{"IEC long name", "2 mebibytes", 2 * MiB, false},
+		{"SI unit rejected", "1GB", 0, true},
+		{"SI long name rejected", "1 kilobyte", 0, true},
+		{"SI in compound rejected", "1GiB 1MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSize(tt.input, ParseOptions{IECOnly: true})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSizeArithmetic(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,160 @@
+package bytex
+
+import (
+	"errors"
+	"io"
+	"slices"
+)
+
+var (
+	ErrInvalidCharacter = errors.New("invalid character in encoded text")
+	ErrInvalidChecksum  = errors.New("invalid checksum in encoded text")
+	ErrInvalidTextSize  = errors.New("invalid length of encoded text")
+)
+
+// TextEncoding is a binary-to-text encoding (Base58, Crockford32, Crockford32Check, Z85)
+type TextEncoding interface {
+	// AppendEncode appends the encoded src to dst (no allocation if dst has enough capacity)
+	AppendEncode(dst, src []byte) []byte
+	// AppendDecode appends the decoded src to dst (no allocation if dst has enough capacity)
+	AppendDecode(dst, src []byte) ([]byte, error)
+	// EncodeToString returns the encoded src
+	EncodeToString(src []byte) string
+	// DecodeString returns the decoded s
+	DecodeString(s string) ([]byte, error)
+
+	// blocks returns the raw and text block sizes for streaming (zero when the whole input is encoded at once)
+	blocks() (raw, text int)
+	// ignored returns true for the text characters skipped when decoding
+	ignored(c byte) bool
+}
+
+// Encoder is a writer encoding everything written to it with a TextEncoding.
+// Block encodings (Z85, Crockford32) are written as soon as a block is complete,
+// while encodings of the whole input (Base58, Crockford32Check) are written on Close.
+type Encoder struct {
+	enc TextEncoding
+	w   io.Writer
+	buf []byte
+	out []byte
+	err error
+}
+
+// NewEncoder creates a new encoder writing to w (Close must be called to flush the final block)
+func NewEncoder(enc TextEncoding, w io.Writer) *Encoder {
+	return &Encoder{enc: enc, w: w}
+}
+
+// Write encodes the complete blocks of the data, buffering the rest
+func (e *Encoder) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	// Buffer the data
+	e.buf = append(e.buf, p...)
+
+	// Wait for Close if the whole input is encoded at once
+	raw, _ := e.enc.blocks()
+	if raw == 0 {
+		return len(p), nil
+	}
+
+	// Encode the complete blocks
+	if n := len(e.buf) / raw * raw; n > 0 {
+		e.flush(e.buf[:n])
+		e.buf = e.buf[:copy(e.buf, e.buf[n:])]
+	}
+	return len(p), e.err
+}
+
+// Close encodes the remaining data (it does not close the underlying writer)
+func (e *Encoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	// Encode the remaining data
+	if len(e.buf) > 0 {
+		e.flush(e.buf)
+	}
+	e.buf = nil
+	return e.err
+}
+
+// flush encodes the data to the underlying writer
+func (e *Encoder) flush(data []byte) {
+	e.out = e.enc.AppendEncode(e.out[:0], data)
+	_, e.err = e.w.Write(e.out)
+}
+
+// Decoder is a reader decoding the text read from an underlying reader with a TextEncoding.
+// Whitespace is skipped. Block encodings (Z85, Crockford32) are decoded as soon as a block is complete,
+// while encodings of the whole input (Base58, Crockford32Check) are decoded at EOF.
+type Decoder struct {
+	enc  TextEncoding
+	r    io.Reader
+	in   []byte
+	out  []byte
+	read []byte
+	eof  bool
+	err  error
+}
+
+// NewDecoder creates a new decoder reading from r
+func NewDecoder(enc TextEncoding, r io.Reader) *Decoder {
+	return &Decoder{enc: enc, r: r, read: make([]byte, 4*KiB)}
+}
+
+// Read reads the decoded data
+func (d *Decoder) Read(p []byte) (int, error) {
+	// Decode until some output is available
+	for len(d.out) == 0 {
+		switch {
+		case d.err != nil:
+			return 0, d.err
+		case d.eof:
+			return 0, io.EOF
+		}
+		d.fill()
+	}
+
+	// Return the decoded data
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// fill reads the next text chunk and decodes the complete blocks (or everything at EOF)
+func (d *Decoder) fill() {
+	// Read the next chunk, skipping whitespace and ignored characters
+	n, err := d.r.Read(d.read)
+	for _, c := range d.read[:n] {
+		if !isTextSpace(c) && !d.enc.ignored(c) {
+			d.in = append(d.in, c)
+		}
+	}
+
+	// Select the text to decode
+	var text []byte
+	switch _, block := d.enc.blocks(); {
+	case err == io.EOF:
+		text, d.eof = d.in, true
+	case err != nil:
+		d.err = err
+		return
+	case block > 0:
+		text = d.in[:len(d.in)/block*block]
+	default:
+		return
+	}
+
+	// Decode the text, keeping the incomplete block for later
+	d.out, d.err = d.enc.AppendDecode(slices.Grow(d.out[:0], len(text)), text)
+	d.in = d.in[:copy(d.in, d.in[len(text):])]
+}
+
+// isTextSpace returns true for whitespace skipped by decoders
+func isTextSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,233 @@
+package bytex
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+		text string
+	}{
+		{"Empty", nil, ""},
+		{"Text", []byte("Hello World!"), "2NEpo7TZRRrLZSi2U"},
+		{"Leading zeros", []byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd}, "11233QC4"},
+		{"Only zeros", []byte{0x00, 0x00}, "11"},
+		{"Single byte", []byte{0xff}, "5Q"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.text, Base58.EncodeToString(tt.raw))
+			decoded, err := Base58.DecodeString(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.raw), len(decoded))
+			assert.True(t, bytes.Equal(tt.raw, decoded))
+		})
+	}
+
+	t.Run("Invalid characters", func(t *testing.T) {
+		for _, text := range []string{"0OIl", "abc0", "12 3"} {
+			_, err := Base58.DecodeString(text)
+			assert.ErrorIs(t, err, ErrInvalidCharacter, text)
+		}
+	})
+}
+
+func TestCrockford32(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   []byte
+		text  string
+		check string
+	}{
+		{"Empty", nil, "", "0"},
+		{"Text", []byte("hello"), "D1JPRV3F", "J"},
+		{"Leading zeros", []byte{0, 1, 2, 3, 4, 5}, "000G40R40M", "A"},
+		{"Partial symbol", []byte{0xff}, "ZW", "~"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.text, Crockford32.EncodeToString(tt.raw))
+			assert.Equal(t, tt.text+tt.check, Crockford32Check.EncodeToString(tt.raw))
+
+			decoded, err := Crockford32.DecodeString(tt.text)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.raw, decoded))
+
+			decoded, err = Crockford32Check.DecodeString(tt.text + tt.check)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.raw, decoded))
+		})
+	}
+
+	t.Run("Aliases, case and hyphens", func(t *testing.T) {
+		decoded, err := Crockford32.DecodeString("d1jp-rv3f")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(decoded))
+
+		decoded, err = Crockford32.DecodeString("OOOG-4OR4-OM")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0, 1, 2, 3, 4, 5}, decoded)
+
+		decoded, err = Crockford32Check.DecodeString("zw-~")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0xff}, decoded)
+
+		decoded, err = Crockford32.DecodeString("1iIlL")
+		assert.ErrorIs(t, err, ErrInvalidTextSize)
+		assert.Empty(t, decoded)
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		_, err := Crockford32.DecodeString("D1JU")
+		assert.ErrorIs(t, err, ErrInvalidCharacter)
+
+		_, err = Crockford32.DecodeString("D")
+		assert.ErrorIs(t, err, ErrInvalidTextSize)
+
+		_, err = Crockford32.DecodeString("ZZ")
+		assert.ErrorIs(t, err, ErrInvalidTextSize)
+
+		_, err = Crockford32Check.DecodeString("D1JPRV3FK")
+		assert.ErrorIs(t, err, ErrInvalidChecksum)
+
+		_, err = Crockford32Check.DecodeString("")
+		assert.ErrorIs(t, err, ErrInvalidTextSize)
+	})
+}
+
+func TestZ85(t *testing.T) {
+	t.Run("Specification vector", func(t *testing.T) {
+		raw := []byte{0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B}
+		assert.Equal(t, "HelloWorld", Z85.EncodeToString(raw))
+
+		decoded, err := Z85.DecodeString("HelloWorld")
+		assert.NoError(t, err)
+		assert.Equal(t, raw, decoded)
+	})
+
+	t.Run("Partial blocks", func(t *testing.T) {
+		for n := range 9 {
+			for _, fill := range []byte{0x00, 0x5A, 0xFF} {
+				raw := bytes.Repeat([]byte{fill}, n)
+				text := Z85.EncodeToString(raw)
+				assert.Len(t, text, n/4*5+min(n%4, 1)*(n%4+1))
+
+				decoded, err := Z85.DecodeString(text)
+				assert.NoError(t, err)
+				assert.True(t, bytes.Equal(raw, decoded))
+			}
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		_, err := Z85.DecodeString("Hello\"orld")
+		assert.ErrorIs(t, err, ErrInvalidCharacter)
+
+		_, err = Z85.DecodeString("HelloW")
+		assert.ErrorIs(t, err, ErrInvalidTextSize)
+
+		_, err = Z85.DecodeString("#####")
+		assert.ErrorIs(t, err, ErrInvalidCharacter)
+	})
+}
+
+func TestTextEncodingAppend(t *testing.T) {
+	raw := make([]byte, 32)
+	_, _ = rand.Read(raw)
+
+	for name, enc := range map[string]TextEncoding{
+		"Base58":           Base58,
+		"Crockford32":      Crockford32,
+		"Crockford32Check": Crockford32Check,
+		"Z85":              Z85,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Appending keeps the prefix
+			text := enc.AppendEncode([]byte("id:"), raw)
+			assert.True(t, bytes.HasPrefix(text, []byte("id:")))
+
+			decoded, err := enc.AppendDecode([]byte{0xAA}, text[3:])
+			assert.NoError(t, err)
+			assert.Equal(t, append([]byte{0xAA}, raw...), decoded)
+
+			// No allocation with enough capacity
+			encodeBuf := make([]byte, 0, 128)
+			decodeBuf := make([]byte, 0, 128)
+			allocs := testing.AllocsPerRun(100, func() {
+				_ = enc.AppendEncode(encodeBuf, raw)
+				_, _ = enc.AppendDecode(decodeBuf, text[3:])
+			})
+			assert.Zero(t, allocs)
+		})
+	}
+}
+
+func TestTextEncodingStream(t *testing.T) {
+	raw := make([]byte, 1000)
+	_, _ = rand.Read(raw)
+
+	for name, enc := range map[string]TextEncoding{
+		"Base58":           Base58,
+		"Crockford32":      Crockford32,
+		"Crockford32Check": Crockford32Check,
+		"Z85":              Z85,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Encode in small writes
+			var text bytes.Buffer
+			encoder := NewEncoder(enc, &text)
+			for chunk := range slicesChunk(raw, 7) {
+				_, err := encoder.Write(chunk)
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, encoder.Close())
+			assert.Equal(t, enc.EncodeToString(raw), text.String())
+
+			// Decode one byte at a time, with line breaks
+			wrapped := strings.Join(splitEvery(text.String(), 60), "\n")
+			decoder := NewDecoder(enc, iotest.OneByteReader(strings.NewReader(wrapped)))
+			decoded, err := io.ReadAll(decoder)
+			assert.NoError(t, err)
+			assert.Equal(t, raw, decoded)
+		})
+	}
+
+	t.Run("Decoding errors are reported", func(t *testing.T) {
+		_, err := io.ReadAll(NewDecoder(Z85, strings.NewReader("HelloWorld\"orld")))
+		assert.ErrorIs(t, err, ErrInvalidCharacter)
+	})
+}
+
+// slicesChunk yields consecutive chunks of at most n bytes
+func slicesChunk(b []byte, n int) func(func([]byte) bool) {
+	return func(yield func([]byte) bool) {
+		for len(b) > 0 {
+			k := min(n, len(b))
+			if !yield(b[:k]) {
+				return
+			}
+			b = b[k:]
+		}
+	}
+}
+
+// splitEvery splits the string into parts of n characters
+func splitEvery(s string, n int) []string {
+	var parts []string
+	for len(s) > n {
+		parts = append(parts, s[:n])
+		s = s[n:]
+	}
+	return append(parts, s)
+}
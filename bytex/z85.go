@@ -0,0 +1,110 @@
+package bytex
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+// z85Alphabet is the ZeroMQ Z85 alphabet (safe in source code, XML and JSON strings)
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// z85DecodeMap maps from character to digit (0xFF for invalid characters)
+var z85DecodeMap = newDecodeMap(z85Alphabet)
+
+// Z85 is the ZeroMQ Z85 encoding (RFC 32), 4 bytes are encoded as 5 characters.
+// Inputs whose length is a multiple of 4 produce standard Z85, other lengths encode
+// the final partial block of n bytes as n+1 characters (as Ascii85 does).
+var Z85 TextEncoding = z85Encoding{}
+
+// z85Encoding implements the Z85 encoding
+type z85Encoding struct{}
+
+// AppendEncode appends the Z85 encoding of src to dst
+func (z85Encoding) AppendEncode(dst, src []byte) []byte {
+	// Reserve the space for the characters
+	dst = slices.Grow(dst, (len(src)*5+3)/4)
+
+	// Encode the blocks (the last one padded with zeros)
+	for len(src) > 0 {
+		var block [4]byte
+		n := copy(block[:], src)
+		src = src[n:]
+
+		// Convert the block to 5 base 85 digits (most significant first)
+		value := binary.BigEndian.Uint32(block[:])
+		var digits [5]byte
+		for i := 4; i >= 0; i-- {
+			digits[i] = z85Alphabet[value%85]
+			value /= 85
+		}
+
+		// Keep n+1 characters of a partial block
+		dst = append(dst, digits[:n+1]...)
+	}
+	return dst
+}
+
+// AppendDecode appends the bytes decoded from the Z85 src to dst
+func (z85Encoding) AppendDecode(dst, src []byte) ([]byte, error) {
+	start := len(dst)
+
+	// Reject lengths that cannot be produced by the encoder
+	if len(src)%5 == 1 {
+		return dst, fmt.Errorf("%w: %d", ErrInvalidTextSize, len(src))
+	}
+
+	// Decode the blocks (the last one padded with the highest digit)
+	dst = slices.Grow(dst, len(src)*4/5)
+	for offset := 0; offset < len(src); offset += 5 {
+		chunk := src[offset:min(offset+5, len(src))]
+
+		// Convert the 5 base 85 digits to a block
+		var value uint64
+		for i := range 5 {
+			digit := byte(84)
+			if i < len(chunk) {
+				digit = z85DecodeMap[chunk[i]]
+				if digit == 0xFF {
+					return dst[:start], fmt.Errorf("%w: %q at offset %d", ErrInvalidCharacter, chunk[i], offset+i)
+				}
+			}
+			value = value*85 + uint64(digit)
+		}
+
+		// Reject blocks that overflow 32 bits
+		if value > math.MaxUint32 {
+			return dst[:start], fmt.Errorf("%w: block at offset %d overflows", ErrInvalidCharacter, offset)
+		}
+
+		// Keep len-1 bytes of a partial block
+		dst = binary.BigEndian.AppendUint32(dst, uint32(value))
+		if len(chunk) < 5 {
+			dst = dst[:len(dst)-(5-len(chunk))]
+		}
+	}
+	return dst, nil
+}
+
+// EncodeToString returns the Z85 encoding of src
+func (e z85Encoding) EncodeToString(src []byte) string {
+	return stringsx.FromBytes(e.AppendEncode(nil, src))
+}
+
+// DecodeString returns the bytes decoded from the Z85 string s
+func (e z85Encoding) DecodeString(s string) ([]byte, error) {
+	return e.AppendDecode(nil, stringsx.ToBytes(s))
+}
+
+// blocks returns 4 bytes per 5 characters
+func (z85Encoding) blocks() (int, int) {
+	return 4, 5
+}
+
+// ignored returns false, Z85 does not ignore any character
+func (z85Encoding) ignored(byte) bool {
+	return false
+}
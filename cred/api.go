@@ -1,5 +1,16 @@
 package cred
 
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// IsNotFound returns true if the error means the credential does not exist (as opposed to a backend failure)
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrSecretNotFound) || errors.Is(err, ErrEnvVarNotFound) || errors.Is(err, keyring.ErrNotFound)
+}
+
 // Identity stores a pair of user - secret
 type Identity struct {
 	User   string
@@ -43,3 +54,27 @@ type IdentityManager interface {
 	IdentityReader
 	IdentityWriter
 }
+
+// TOTPManager generic API for the TOTP seed stored next to an identity
+type TOTPManager interface {
+	SetTOTP(totp TOTP) error
+	GetTOTP() (TOTP, error)
+	TOTPCode() (string, error)
+	DeleteTOTP() error
+}
+
+// SecureManager generic API for reading/writing credentials with redacted secrets
+// (providers still handle the plaintext as strings while reading and writing)
+type SecureManager interface {
+	GetSecure() (SecureIdentity, error)
+	GetSecretSecure() (*Secret, error)
+	SetAllSecure(identity SecureIdentity) error
+	SetSecretSecure(secret *Secret) error
+}
+
+// SecureIdentityManager generic API for reading/writing credentials, redacted secrets and TOTP seeds
+type SecureIdentityManager interface {
+	IdentityManager
+	SecureManager
+	TOTPManager
+}
@@ -0,0 +1,139 @@
+package cred
+
+import (
+	"errors"
+
+	"github.com/r3dpixel/toolkit/trace"
+)
+
+// WritePolicy the providers a ChainProvider writes to (Set and Delete)
+type WritePolicy byte
+
+const (
+	WriteFirst  WritePolicy = iota // Writes to the first provider only
+	WriteAll                       // Writes to every provider
+	WriteOrigin                    // Writes to the first provider having the key (the first provider if none has it)
+)
+
+// ChainProvider implements IdentityProvider on top of other providers, reading from the first provider having the key.
+// Providers failing to read are skipped, so a later provider can stand in for an unavailable backend.
+type ChainProvider struct {
+	providers []IdentityProvider
+	policy    WritePolicy
+}
+
+// NewChainProvider creates a new chain provider reading from the providers in order (writes use WriteFirst)
+func NewChainProvider(providers ...IdentityProvider) *ChainProvider {
+	return &ChainProvider{
+		providers: providers,
+		policy:    WriteFirst,
+	}
+}
+
+// WithWritePolicy sets the write policy of the chain
+func (c *ChainProvider) WithWritePolicy(policy WritePolicy) *ChainProvider {
+	c.policy = policy
+	return c
+}
+
+// Get retrieves the value for the given key from the first provider having it.
+// If no provider has the key the error satisfies IsNotFound, unless a provider failed.
+func (c *ChainProvider) Get(key string) (string, error) {
+	value, _, err := c.lookup(key)
+	return value, err
+}
+
+// Set stores a key-value pair in the providers selected by the write policy
+func (c *ChainProvider) Set(key, value string) error {
+	return c.write(key, "set failed", func(p IdentityProvider) error {
+		return p.Set(key, value)
+	})
+}
+
+// Delete removes a key-value pair from the providers selected by the write policy
+func (c *ChainProvider) Delete(key string) error {
+	return c.write(key, "delete failed", func(p IdentityProvider) error {
+		return p.Delete(key)
+	})
+}
+
+// CredLabel returns the label of the first provider
+func (c *ChainProvider) CredLabel() string {
+	if len(c.providers) == 0 {
+		return ""
+	}
+	return c.providers[0].CredLabel()
+}
+
+// Providers returns the providers of the chain
+func (c *ChainProvider) Providers() []IdentityProvider {
+	return c.providers
+}
+
+// lookup returns the value and the index of the first provider having the key
+func (c *ChainProvider) lookup(key string) (string, int, error) {
+	var notFound, failures []error
+	for i, p := range c.providers {
+		// Return the first value found
+		value, err := p.Get(key)
+		if err == nil {
+			return value, i, nil
+		}
+
+		// Collect the error and move on to the next provider
+		if IsNotFound(err) {
+			notFound = append(notFound, providerError(p, key, err))
+		} else {
+			failures = append(failures, providerError(p, key, err))
+		}
+	}
+
+	// Report the key as not found, unless a provider failed (it might have the key)
+	errs := failures
+	if len(failures) == 0 {
+		errs = append([]error{ErrSecretNotFound}, notFound...)
+	}
+	return "", -1, trace.Error().
+		Msg("get failed").
+		Field(trace.LABEL, c.CredLabel()).
+		Field(trace.KEY, key).
+		Wrap(errors.Join(errs...))
+}
+
+// write applies the operation to the providers selected by the write policy, aggregating the errors
+func (c *ChainProvider) write(key, msg string, op func(p IdentityProvider) error) error {
+	// Select the providers
+	if len(c.providers) == 0 {
+		return nil
+	}
+	targets := c.providers[:1]
+	switch c.policy {
+	case WriteAll:
+		targets = c.providers
+	case WriteOrigin:
+		if _, i, err := c.lookup(key); err == nil {
+			targets = c.providers[i : i+1]
+		}
+	}
+
+	// Apply the operation to every selected provider
+	var errs []error
+	for _, p := range targets {
+		if err := op(p); err != nil {
+			errs = append(errs, providerError(p, key, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return trace.Error().
+		Msg(msg).
+		Field(trace.LABEL, c.CredLabel()).
+		Field(trace.KEY, key).
+		Wrap(errors.Join(errs...))
+}
+
+// providerError wraps the error of a provider with its label and the key
+func providerError(p IdentityProvider, key string, err error) error {
+	return trace.Error().Field(trace.LABEL, p.CredLabel()).Field(trace.KEY, key).Wrap(err)
+}
@@ -0,0 +1,192 @@
+package cred
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, IsNotFound(ErrSecretNotFound))
+	assert.True(t, IsNotFound(ErrEnvVarNotFound))
+	assert.True(t, IsNotFound(keyring.ErrNotFound))
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", keyring.ErrNotFound)))
+	assert.False(t, IsNotFound(errors.New("backend failure")))
+	assert.False(t, IsNotFound(nil))
+}
+
+func TestChainProvider_Get(t *testing.T) {
+	override := NewMemoryProvider("override")
+	primary := NewMemoryProvider("primary")
+	fallback := NewMemoryProvider("fallback")
+	c := NewChainProvider(override, primary, fallback)
+
+	require.NoError(t, primary.Set("user", "primary-user"))
+	require.NoError(t, fallback.Set("user", "fallback-user"))
+	require.NoError(t, fallback.Set("secret", "fallback-secret"))
+
+	t.Run("Reads from the first provider having the key", func(t *testing.T) {
+		value, err := c.Get("user")
+		assert.NoError(t, err)
+		assert.Equal(t, "primary-user", value)
+
+		value, err = c.Get("secret")
+		assert.NoError(t, err)
+		assert.Equal(t, "fallback-secret", value)
+	})
+
+	t.Run("Earlier providers override later ones", func(t *testing.T) {
+		require.NoError(t, override.Set("user", "override-user"))
+		t.Cleanup(func() { _ = override.Delete("user") })
+
+		value, err := c.Get("user")
+		assert.NoError(t, err)
+		assert.Equal(t, "override-user", value)
+	})
+
+	t.Run("Failing providers are skipped", func(t *testing.T) {
+		errUnavailable := errors.New("backend unavailable")
+		primary.Fail(OpGet, AnyKey, errUnavailable)
+		t.Cleanup(primary.ClearFailures)
+
+		value, err := c.Get("user")
+		assert.NoError(t, err)
+		assert.Equal(t, "fallback-user", value)
+	})
+
+	t.Run("Missing key is not found", func(t *testing.T) {
+		_, err := c.Get("missing")
+		assert.True(t, IsNotFound(err))
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+
+		var tracedErr *trace.Err
+		require.ErrorAs(t, err, &tracedErr)
+		assert.Equal(t, "override", tracedErr.GetField(trace.LABEL))
+		assert.Equal(t, "missing", tracedErr.GetField(trace.KEY))
+	})
+
+	t.Run("Missing key with a failing provider is a failure", func(t *testing.T) {
+		errUnavailable := errors.New("backend unavailable")
+		fallback.Fail(OpGet, "missing", errUnavailable)
+		t.Cleanup(fallback.ClearFailures)
+
+		_, err := c.Get("missing")
+		assert.False(t, IsNotFound(err))
+		assert.ErrorIs(t, err, errUnavailable)
+	})
+
+	t.Run("Not found errors of different backends are unified", func(t *testing.T) {
+		env := NewEnvProvider(fmt.Sprintf("cred-test-%s", t.Name()))
+		_, err := NewChainProvider(env, NewMemoryProvider("memory")).Get("missing")
+		assert.True(t, IsNotFound(err))
+		assert.ErrorIs(t, err, ErrEnvVarNotFound)
+	})
+
+	t.Run("Empty chain", func(t *testing.T) {
+		empty := NewChainProvider()
+		_, err := empty.Get("key")
+		assert.True(t, IsNotFound(err))
+		assert.NoError(t, empty.Set("key", "value"))
+		assert.Equal(t, "", empty.CredLabel())
+	})
+}
+
+func TestChainProvider_WritePolicies(t *testing.T) {
+	newChain := func(policy WritePolicy) (*ChainProvider, *MemoryProvider, *MemoryProvider) {
+		first := NewMemoryProvider("first")
+		second := NewMemoryProvider("second")
+		return NewChainProvider(first, second).WithWritePolicy(policy), first, second
+	}
+
+	t.Run("WriteFirst", func(t *testing.T) {
+		c, first, second := newChain(WriteFirst)
+		require.NoError(t, second.Set("key", "old"))
+
+		require.NoError(t, c.Set("key", "value"))
+		assert.Equal(t, map[string]string{"key": "value"}, first.Values())
+		assert.Equal(t, map[string]string{"key": "old"}, second.Values())
+
+		require.NoError(t, c.Delete("key"))
+		assert.False(t, first.Has("key"))
+		assert.True(t, second.Has("key"))
+	})
+
+	t.Run("WriteAll", func(t *testing.T) {
+		c, first, second := newChain(WriteAll)
+
+		require.NoError(t, c.Set("key", "value"))
+		assert.Equal(t, map[string]string{"key": "value"}, first.Values())
+		assert.Equal(t, map[string]string{"key": "value"}, second.Values())
+
+		require.NoError(t, c.Delete("key"))
+		assert.Empty(t, first.Values())
+		assert.Empty(t, second.Values())
+	})
+
+	t.Run("WriteOrigin", func(t *testing.T) {
+		c, first, second := newChain(WriteOrigin)
+		require.NoError(t, second.Set("key", "old"))
+
+		require.NoError(t, c.Set("key", "value"))
+		assert.Empty(t, first.Values())
+		assert.Equal(t, map[string]string{"key": "value"}, second.Values())
+
+		require.NoError(t, c.Set("new", "value"))
+		assert.Equal(t, map[string]string{"new": "value"}, first.Values())
+
+		require.NoError(t, c.Delete("key"))
+		assert.False(t, second.Has("key"))
+	})
+
+	t.Run("Errors are aggregated with label and key", func(t *testing.T) {
+		c, first, second := newChain(WriteAll)
+		errFirst := errors.New("first failure")
+		errSecond := errors.New("second failure")
+		first.Fail(OpSet, AnyKey, errFirst)
+		second.Fail(OpSet, AnyKey, errSecond)
+
+		err := c.Set("key", "value")
+		assert.ErrorIs(t, err, errFirst)
+		assert.ErrorIs(t, err, errSecond)
+		assert.False(t, IsNotFound(err))
+
+		var tracedErr *trace.Err
+		require.ErrorAs(t, err, &tracedErr)
+		assert.Equal(t, "first", tracedErr.GetField(trace.LABEL))
+		assert.Equal(t, "key", tracedErr.GetField(trace.KEY))
+		assert.Contains(t, err.Error(), "set failed")
+	})
+
+	t.Run("Partial failures still write the other providers", func(t *testing.T) {
+		c, first, second := newChain(WriteAll)
+		first.Fail(OpSet, AnyKey, errors.New("failure"))
+
+		assert.Error(t, c.Set("key", "value"))
+		assert.True(t, second.Has("key"))
+	})
+}
+
+func TestChainProvider_Manager(t *testing.T) {
+	first := NewMemoryProvider("first")
+	second := NewMemoryProvider("second")
+	m := NewManagerWithProvider(NewChainProvider(first, second).WithWritePolicy(WriteAll))
+
+	for _, tc := range []struct {
+		name string
+		fn   func(t *testing.T, m IdentityManager, notFoundErr error)
+	}{
+		{"Lifecycle", testManagerLifecycle},
+		{"SetPartialPayloads", testManagerSetPartialPayloads},
+		{"GetPartialFailure", testManagerGetPartialFailure},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_ = m.Delete()
+			tc.fn(t, m, ErrSecretNotFound)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package credtest provides test helpers for the cred package
+package credtest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/r3dpixel/toolkit/cred/internal/keyringstore"
+	"github.com/zalando/go-keyring"
+)
+
+// MockKeyRing switches the cred keyring functions to an in-memory backend for the duration of the test
+// (every operation fails with err if given), restoring the previous backend when the test ends.
+// The swap itself is safe for concurrent use, but the backend is global: parallel tests share the latest mock.
+func MockKeyRing(tb testing.TB, err ...error) {
+	tb.Helper()
+
+	// Create the mock backend
+	mock := &mockKeyRing{values: map[string]map[string]string{}}
+	if len(err) > 0 {
+		mock.err = err[0]
+	}
+
+	// Swap the backend, restoring the previous one at the end of the test
+	previous := keyringstore.Swap(mock)
+	tb.Cleanup(func() {
+		keyringstore.Swap(previous)
+	})
+}
+
+// mockKeyRing implements keyring.Keyring in memory (safe for concurrent use)
+type mockKeyRing struct {
+	mu     sync.Mutex
+	values map[string]map[string]string
+	err    error
+}
+
+// Get retrieves a value from the mock
+func (m *mockKeyRing) Get(service, user string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return "", m.err
+	}
+	value, ok := m.values[service][user]
+	if !ok {
+		return "", keyring.ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores a value in the mock
+func (m *mockKeyRing) Set(service, user, password string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	if m.values[service] == nil {
+		m.values[service] = map[string]string{}
+	}
+	m.values[service][user] = password
+	return nil
+}
+
+// Delete removes a value from the mock
+func (m *mockKeyRing) Delete(service, user string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	if _, ok := m.values[service][user]; !ok {
+		return keyring.ErrNotFound
+	}
+	delete(m.values[service], user)
+	return nil
+}
+
+// DeleteAll removes every value of the service from the mock
+func (m *mockKeyRing) DeleteAll(service string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.values, service)
+	return nil
+}
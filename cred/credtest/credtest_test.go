@@ -0,0 +1,94 @@
+package credtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r3dpixel/toolkit/cred"
+	"github.com/r3dpixel/toolkit/cred/internal/keyringstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func TestMockKeyRing(t *testing.T) {
+	t.Run("Provider uses the mock backend", func(t *testing.T) {
+		MockKeyRing(t)
+		p := cred.NewKeyProvider("cred-test-mock")
+
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, keyring.ErrNotFound)
+
+		assert.NoError(t, p.Set("key", "value"))
+		value, err := p.Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		assert.NoError(t, p.Delete("key"))
+		assert.NoError(t, p.Delete("key"))
+	})
+
+	t.Run("Previous backend is restored after the test", func(t *testing.T) {
+		MockKeyRing(t)
+		outer := keyringstore.Backend()
+
+		t.Run("Store", func(t *testing.T) {
+			MockKeyRing(t)
+			assert.NotSame(t, outer, keyringstore.Backend())
+			assert.NoError(t, cred.ToKeyRing("cred-test-mock", "leftover", "value"))
+		})
+
+		assert.Same(t, outer, keyringstore.Backend())
+		_, err := cred.FromKeyRing("cred-test-mock", "leftover")
+		assert.ErrorIs(t, err, keyring.ErrNotFound)
+	})
+
+	t.Run("OS keyring is restored after the test", func(t *testing.T) {
+		t.Run("Mock", func(t *testing.T) {
+			MockKeyRing(t)
+			assert.IsType(t, &mockKeyRing{}, keyringstore.Backend())
+		})
+
+		assert.Equal(t, keyring.Keyring(keyringstore.OS{}), keyringstore.Backend())
+	})
+
+	t.Run("Parallel tests swap the backend safely", func(t *testing.T) {
+		for range 4 {
+			t.Run("Parallel", func(t *testing.T) {
+				t.Parallel()
+				MockKeyRing(t)
+				_, _ = cred.FromKeyRing("cred-test-mock", "key")
+			})
+		}
+	})
+
+	t.Run("Every operation fails with the error", func(t *testing.T) {
+		failure := errors.New("keyring locked")
+		MockKeyRing(t, failure)
+
+		_, err := cred.FromKeyRing("cred-test-mock", "key")
+		assert.ErrorIs(t, err, failure)
+		assert.ErrorIs(t, cred.ToKeyRing("cred-test-mock", "key", "value"), failure)
+		assert.ErrorIs(t, cred.DeleteKeyRing("cred-test-mock", "key"), failure)
+	})
+
+	t.Run("Manager works concurrently on the mock", func(t *testing.T) {
+		MockKeyRing(t)
+		m := cred.NewManager("cred-test-mock", cred.KeyRing)
+
+		done := make(chan struct{})
+		for range 8 {
+			go func() {
+				defer func() { done <- struct{}{} }()
+				_ = m.SetAll(cred.Identity{User: "user", Secret: "secret"})
+				_, _ = m.Get()
+			}()
+		}
+		for range 8 {
+			<-done
+		}
+
+		identity, err := m.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, cred.Identity{User: "user", Secret: "secret"}, identity)
+	})
+}
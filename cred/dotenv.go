@@ -0,0 +1,328 @@
+package cred
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+var ErrDotenvSyntax = errors.New("invalid dotenv syntax")
+
+const dotenvExport = "export"
+
+// dotenvEntry is a statement of a dotenv file (a variable, or a blank or comment line kept as is)
+type dotenvEntry struct {
+	raw    string // Original text, including the lines of multiline values (without the final newline)
+	name   string // Name of the variable (empty for blank and comment lines)
+	value  string // Value as written, without the quotes (escapes and interpolation are resolved on lookup)
+	quote  byte   // Quote of the value (0 if unquoted)
+	export bool   // The statement uses the export prefix
+}
+
+// dotenvFile is a parsed dotenv file, keeping the comments and ordering for writing it back
+type dotenvFile struct {
+	entries []dotenvEntry
+}
+
+// ParseDotenv parses a dotenv file and returns its variables, resolving the quotes, escapes and interpolation.
+// Interpolated variables (${VAR}, ${VAR:-default} or $VAR) are looked up in the preceding statements, then
+// in the process environment. Later statements override earlier ones.
+func ParseDotenv(data []byte) (map[string]string, error) {
+	f, err := parseDotenv(stringsx.FromBytes(data))
+	if err != nil {
+		return nil, err
+	}
+	return f.values(os.LookupEnv), nil
+}
+
+// parseDotenv parses the statements of a dotenv file
+func parseDotenv(data string) (*dotenvFile, error) {
+	f := &dotenvFile{}
+	line := 1
+	for pos := 0; pos < len(data); {
+		// Find the end of the line
+		end := strings.IndexByte(data[pos:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += pos
+		}
+
+		// Keep blank and comment lines as is
+		text := strings.TrimSpace(data[pos:end])
+		if text == "" || text[0] == '#' {
+			f.entries = append(f.entries, dotenvEntry{raw: data[pos:end]})
+			pos, line = end+1, line+1
+			continue
+		}
+
+		// Parse the statement (possibly spanning several lines)
+		entry, next, err := parseDotenvStatement(data, pos, end)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrDotenvSyntax, line, err)
+		}
+		f.entries = append(f.entries, entry)
+		line += strings.Count(entry.raw, "\n") + 1
+		pos = next + 1
+	}
+	return f, nil
+}
+
+// parseDotenvStatement parses the statement starting on the line [start, end), returning the end of its last line
+func parseDotenvStatement(data string, start, end int) (dotenvEntry, int, error) {
+	entry := dotenvEntry{}
+	i := skipDotenvSpaces(data, start)
+
+	// Parse the export prefix
+	if rest := data[i:end]; strings.HasPrefix(rest, dotenvExport) && len(rest) > len(dotenvExport) &&
+		(rest[len(dotenvExport)] == ' ' || rest[len(dotenvExport)] == '\t') {
+		entry.export = true
+		i = skipDotenvSpaces(data, i+len(dotenvExport))
+	}
+
+	// Parse the name
+	nameStart := i
+	for i < end && isDotenvNameChar(data[i]) {
+		i++
+	}
+	entry.name = data[nameStart:i]
+	if entry.name == "" {
+		return entry, 0, errors.New("missing variable name")
+	}
+
+	// Parse the separator
+	i = skipDotenvSpaces(data, i)
+	if i >= end || data[i] != '=' {
+		return entry, 0, fmt.Errorf("missing '=' after %s", entry.name)
+	}
+	i = skipDotenvSpaces(data, i+1)
+
+	// Parse an unquoted value, up to the end of the line or an inline comment
+	if i >= end || (data[i] != '"' && data[i] != '\'') {
+		value := strings.TrimRight(data[i:end], " \t\r")
+		if j := strings.Index(value, " #"); j >= 0 {
+			value = strings.TrimRight(value[:j], " \t")
+		}
+		if j := strings.Index(value, "\t#"); j >= 0 {
+			value = strings.TrimRight(value[:j], " \t")
+		}
+		if strings.HasPrefix(value, "#") {
+			value = ""
+		}
+		entry.value = value
+		entry.raw = data[start:end]
+		return entry, end, nil
+	}
+
+	// Parse a quoted value, possibly spanning several lines
+	entry.quote = data[i]
+	closing := -1
+	for j := i + 1; j < len(data); j++ {
+		if data[j] == '\\' && entry.quote == '"' {
+			j++
+			continue
+		}
+		if data[j] == entry.quote {
+			closing = j
+			break
+		}
+	}
+	if closing < 0 {
+		return entry, 0, fmt.Errorf("unterminated %c quote in %s", entry.quote, entry.name)
+	}
+	entry.value = data[i+1 : closing]
+
+	// Only a comment may follow the closing quote
+	lineEnd := strings.IndexByte(data[closing:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(data)
+	} else {
+		lineEnd += closing
+	}
+	if rest := strings.TrimSpace(data[closing+1 : lineEnd]); rest != "" && rest[0] != '#' {
+		return entry, 0, fmt.Errorf("unexpected %q after the value of %s", rest, entry.name)
+	}
+	entry.raw = data[start:lineEnd]
+	return entry, lineEnd, nil
+}
+
+// values resolves the variables of the file, looking up missing interpolated variables with lookup (if not nil)
+func (f *dotenvFile) values(lookup func(name string) (string, bool)) map[string]string {
+	vars := map[string]string{}
+	resolve := func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		if lookup != nil {
+			return lookup(name)
+		}
+		return "", false
+	}
+	for _, entry := range f.entries {
+		if entry.name != "" {
+			vars[entry.name] = resolveDotenvValue(entry.value, entry.quote, resolve)
+		}
+	}
+	return vars
+}
+
+// set updates the last statement of the variable, or appends a new one
+func (f *dotenvFile) set(name, value string) {
+	for i := len(f.entries) - 1; i >= 0; i-- {
+		if f.entries[i].name == name {
+			f.entries[i] = newDotenvEntry(name, value, f.entries[i].export)
+			return
+		}
+	}
+	f.entries = append(f.entries, newDotenvEntry(name, value, false))
+}
+
+// delete removes every statement of the variable, returning true if any was removed
+func (f *dotenvFile) delete(name string) bool {
+	n := len(f.entries)
+	f.entries = slices.DeleteFunc(f.entries, func(entry dotenvEntry) bool {
+		return entry.name == name
+	})
+	return len(f.entries) != n
+}
+
+// bytes returns the text of the file
+func (f *dotenvFile) bytes() []byte {
+	var b strings.Builder
+	for _, entry := range f.entries {
+		b.WriteString(entry.raw)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// newDotenvEntry creates a statement for the variable, quoting and escaping the value if needed
+func newDotenvEntry(name, value string, export bool) dotenvEntry {
+	var b strings.Builder
+	if export {
+		b.WriteString(dotenvExport + " ")
+	}
+	b.WriteString(name)
+	b.WriteByte('=')
+
+	// Write safe values unquoted
+	if !strings.ContainsFunc(value, func(r rune) bool { return !isDotenvSafeChar(r) }) {
+		b.WriteString(value)
+		return dotenvEntry{raw: b.String(), name: name, value: value, export: export}
+	}
+
+	// Write other values double-quoted, escaping the special characters
+	var quoted strings.Builder
+	for _, r := range value {
+		switch r {
+		case '\\', '"', '$':
+			quoted.WriteByte('\\')
+			quoted.WriteRune(r)
+		case '\n':
+			quoted.WriteString(`\n`)
+		case '\r':
+			quoted.WriteString(`\r`)
+		case '\t':
+			quoted.WriteString(`\t`)
+		default:
+			quoted.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	b.WriteString(quoted.String())
+	b.WriteByte('"')
+	return dotenvEntry{raw: b.String(), name: name, value: quoted.String(), quote: '"', export: export}
+}
+
+// resolveDotenvValue resolves the escapes (double quotes only) and interpolation (unless single-quoted) of a value
+func resolveDotenvValue(value string, quote byte, lookup func(name string) (string, bool)) string {
+	// Single-quoted values are literal
+	if quote == '\'' {
+		return value
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		// Resolve the escapes of double-quoted values
+		case c == '\\' && quote == '"' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$', '\'':
+				b.WriteByte(value[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(value[i])
+			}
+
+		// Resolve ${VAR} and ${VAR:-default}
+		case c == '$' && i+1 < len(value) && value[i+1] == '{':
+			end := strings.IndexByte(value[i+2:], '}')
+			if end < 0 {
+				b.WriteString(value[i:])
+				return b.String()
+			}
+			name, fallback, hasFallback := strings.Cut(value[i+2:i+2+end], ":-")
+			resolved, ok := lookup(name)
+			if hasFallback && (!ok || resolved == "") {
+				resolved = fallback
+			}
+			b.WriteString(resolved)
+			i += 2 + end
+
+		// Resolve $VAR
+		case c == '$' && i+1 < len(value) && isDotenvNameStart(value[i+1]):
+			end := i + 1
+			for end < len(value) && isDotenvInterpolatedChar(value[end]) {
+				end++
+			}
+			resolved, _ := lookup(value[i+1 : end])
+			b.WriteString(resolved)
+			i = end - 1
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// skipDotenvSpaces returns the index of the first character from i that is not a space or tab
+func skipDotenvSpaces(data string, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// isDotenvNameStart returns true for the characters starting an interpolated variable name
+func isDotenvNameStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// isDotenvInterpolatedChar returns true for the characters of an interpolated variable name
+func isDotenvInterpolatedChar(c byte) bool {
+	return isDotenvNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// isDotenvNameChar returns true for the characters of a variable name
+func isDotenvNameChar(c byte) bool {
+	return isDotenvInterpolatedChar(c) || c == '.' || c == '-'
+}
+
+// isDotenvSafeChar returns true for the characters written without quotes
+func isDotenvSafeChar(r rune) bool {
+	return (r < 0x80 && isDotenvNameChar(byte(r))) || strings.ContainsRune("/:@+,=%~", r)
+}
@@ -0,0 +1,112 @@
+package cred
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("CRED_TEST_HOME", "/home/test")
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{"Empty", "", map[string]string{}},
+		{"Comments and blank lines", "# comment\n\n  # indented\nA=1\n", map[string]string{"A": "1"}},
+		{"Unquoted", "A=value\nB = spaced value  \nC=\n", map[string]string{"A": "value", "B": "spaced value", "C": ""}},
+		{"Inline comments", "A=value # comment\nB=value\t# comment\nC=no#comment\nD= # only comment\n",
+			map[string]string{"A": "value", "B": "value", "C": "no#comment", "D": ""}},
+		{"Export prefix", "export A=1\nexport\tB=2\nexported=3\n", map[string]string{"A": "1", "B": "2", "exported": "3"}},
+		{"Single quotes are literal", `A='value # not a comment ${B} \n'`, map[string]string{"A": `value # not a comment ${B} \n`}},
+		{"Double quote escapes", `A="line\nnext\ttab \"quoted\" \\ \$HOME \x"`,
+			map[string]string{"A": "line\nnext\ttab \"quoted\" \\ $HOME \\x"}},
+		{"Quoted with comment", `A="value" # comment`, map[string]string{"A": "value"}},
+		{"Multiline double quotes", "A=\"first\nsecond\"\nB=2\n", map[string]string{"A": "first\nsecond", "B": "2"}},
+		{"Multiline single quotes", "A='first\n# not a comment\nthird'\n", map[string]string{"A": "first\n# not a comment\nthird"}},
+		{"Interpolation", "USER=alice\nA=${USER}-x\nB=\"$USER/${USER}\"\nC='${USER}'\n",
+			map[string]string{"USER": "alice", "A": "alice-x", "B": "alice/alice", "C": "${USER}"}},
+		{"Interpolation from the environment", "A=${CRED_TEST_HOME}/bin\n", map[string]string{"A": "/home/test/bin"}},
+		{"Interpolation defaults", "EMPTY=\nA=${MISSING_CRED_VAR:-fallback}\nB=${EMPTY:-fallback}\nC=${A:-unused}\n",
+			map[string]string{"EMPTY": "", "A": "fallback", "B": "fallback", "C": "fallback"}},
+		{"Missing interpolation is empty", "A=[${MISSING_CRED_VAR}]\n", map[string]string{"A": "[]"}},
+		{"Unclosed interpolation is literal", "A=${USER\n", map[string]string{"A": "${USER"}},
+		{"Later statements override", "A=1\nA=2\n", map[string]string{"A": "2"}},
+		{"Windows line endings", "A=1\r\nB=\"2\"\r\n", map[string]string{"A": "1", "B": "2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := ParseDotenv([]byte(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestParseDotenv_Errors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		line  string
+	}{
+		{"Missing separator", "A=1\nB\n", "line 2"},
+		{"Missing name", "=value\n", "line 1"},
+		{"Unterminated double quote", "A=1\nB=\"value\n", "line 2"},
+		{"Unterminated single quote", "A='value\n", "line 1"},
+		{"Text after the quotes", "A=\"multi\nline\"\nB=\"value\" extra\n", "line 3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseDotenv([]byte(tc.input))
+			assert.ErrorIs(t, err, ErrDotenvSyntax)
+			assert.Contains(t, err.Error(), tc.line)
+		})
+	}
+}
+
+func TestDotenvFile_Write(t *testing.T) {
+	input := "# Database\nexport DB_USER=admin # inline\n\nDB_PASSWORD=\"multi\nline\"\n# Trailing comment\n"
+
+	t.Run("Unchanged file is written back as is", func(t *testing.T) {
+		f, err := parseDotenv(input)
+		require.NoError(t, err)
+		assert.Equal(t, input, string(f.bytes()))
+	})
+
+	t.Run("Updates keep the position, prefix and comments", func(t *testing.T) {
+		f, err := parseDotenv(input)
+		require.NoError(t, err)
+		f.set("DB_USER", "root")
+		f.set("DB_PASSWORD", "simple")
+		f.set("NEW", "value")
+		assert.Equal(t, "# Database\nexport DB_USER=root\n\nDB_PASSWORD=simple\n# Trailing comment\nNEW=value\n", string(f.bytes()))
+	})
+
+	t.Run("Deletes keep the other lines", func(t *testing.T) {
+		f, err := parseDotenv(input)
+		require.NoError(t, err)
+		assert.True(t, f.delete("DB_PASSWORD"))
+		assert.False(t, f.delete("MISSING"))
+		assert.Equal(t, "# Database\nexport DB_USER=admin # inline\n\n# Trailing comment\n", string(f.bytes()))
+	})
+
+	t.Run("Special values are quoted and round-trip", func(t *testing.T) {
+		values := []string{"", "plain", "with space", "quote\"s", "back\\slash", "$HOME ${HOME}", "new\nline\ttab\r", "# hash", "'single'", "ünïcode"}
+		f := &dotenvFile{}
+		for i, value := range values {
+			f.set(string(rune('A'+i)), value)
+		}
+
+		parsed, err := parseDotenv(string(f.bytes()))
+		require.NoError(t, err)
+		resolved := parsed.values(nil)
+		for i, value := range values {
+			assert.Equal(t, value, resolved[string(rune('A'+i))])
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package cred
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/r3dpixel/toolkit/filex"
+	"github.com/r3dpixel/toolkit/stringsx"
+)
+
+const (
+	defaultDotenvPath       = ".env"
+	defaultDotenvPermission = 0600
+)
+
+// DotenvOptions configures the dotenv file provider
+type DotenvOptions struct {
+	Path      string // Path of the dotenv file (default .env in the working directory)
+	IgnoreEnv bool   // Interpolated variables missing from the file are not looked up in the process environment
+}
+
+// dotenvProvider implements IdentityProvider using a dotenv file (variables named as with the Env mode)
+type dotenvProvider struct {
+	mu        sync.Mutex
+	credLabel string
+	opts      DotenvOptions
+}
+
+// NewDotenvProvider creates a new dotenv file based identity provider.
+// The file is read on every Get and rewritten atomically on changes, keeping its comments and ordering.
+func NewDotenvProvider(credLabel string, opts DotenvOptions) IdentityProvider {
+	// Set default values if needed
+	if opts.Path == "" {
+		opts.Path = defaultDotenvPath
+	}
+
+	// Create the provider
+	return &dotenvProvider{
+		credLabel: credLabel,
+		opts:      opts,
+	}
+}
+
+// Set stores a key-value pair in the dotenv file
+func (p *dotenvProvider) Set(key, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Update the variable
+	f, perm, err := p.load()
+	if err != nil {
+		return err
+	}
+	f.set(dotenvName(p.credLabel, key), value)
+
+	// Write the file back
+	return filex.WriteFileAtomic(p.opts.Path, f.bytes(), perm)
+}
+
+// Get retrieves a value for the given key from the dotenv file
+func (p *dotenvProvider) Get(key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Resolve the variables
+	f, _, err := p.load()
+	if err != nil {
+		return "", err
+	}
+	lookup := os.LookupEnv
+	if p.opts.IgnoreEnv {
+		lookup = nil
+	}
+
+	// Return the value if found
+	value, ok := f.values(lookup)[dotenvName(p.credLabel, key)]
+	if !ok {
+		return "", ErrEnvVarNotFound
+	}
+	return value, nil
+}
+
+// Delete removes a key-value pair from the dotenv file (deleting a missing key is not an error)
+func (p *dotenvProvider) Delete(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Remove the variable, skipping the write if it was missing
+	f, perm, err := p.load()
+	if err != nil {
+		return err
+	}
+	if !f.delete(dotenvName(p.credLabel, key)) {
+		return nil
+	}
+
+	// Write the file back
+	return filex.WriteFileAtomic(p.opts.Path, f.bytes(), perm)
+}
+
+// CredLabel returns the label for the provider
+func (p *dotenvProvider) CredLabel() string {
+	return p.credLabel
+}
+
+// dotenvName returns the variable name of the key as with the Env mode, replacing characters invalid in dotenv files
+func dotenvName(credLabel, key string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && isDotenvNameChar(byte(r)) {
+			return r
+		}
+		return '_'
+	}, toEnvVarName(credLabel, key))
+}
+
+// load parses the dotenv file and returns its permissions (a missing file is empty)
+func (p *dotenvProvider) load() (*dotenvFile, os.FileMode, error) {
+	// Read the file
+	data, err := os.ReadFile(p.opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &dotenvFile{}, defaultDotenvPermission, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Keep the permissions of the file
+	perm := os.FileMode(defaultDotenvPermission)
+	if info, err := os.Stat(p.opts.Path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	// Parse the file
+	f, err := parseDotenv(stringsx.FromBytes(data))
+	return f, perm, err
+}
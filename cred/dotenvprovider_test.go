@@ -0,0 +1,118 @@
+package cred
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotenvProvider_Lifecycle(t *testing.T) {
+	credLabel := fmt.Sprintf("cred-test-%s", t.Name())
+	path := filepath.Join(t.TempDir(), ".env")
+	p := NewDotenvProvider(credLabel, DotenvOptions{Path: path})
+
+	t.Run("Get non-existent value fails", func(t *testing.T) {
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrEnvVarNotFound)
+	})
+
+	t.Run("Set and Get successfully", func(t *testing.T) {
+		require.NoError(t, p.Set("key", "s3cr3t p@ss\"w0rd"))
+
+		value, err := p.Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cr3t p@ss\"w0rd", value)
+	})
+
+	t.Run("Variables are named as with the Env mode", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "CRED-TEST-TESTDOTENVPROVIDER_LIFECYCLE_KEY=")
+	})
+
+	t.Run("New files are created with 0600 permissions", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("permissions are not supported on windows")
+		}
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("Delete and verify", func(t *testing.T) {
+		require.NoError(t, p.Delete("key"))
+		assert.NoError(t, p.Delete("key"), "Deleting a non-existent value should not return an error")
+
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrEnvVarNotFound)
+	})
+
+	t.Run("CredLabel", func(t *testing.T) {
+		assert.Equal(t, credLabel, p.CredLabel())
+	})
+}
+
+func TestDotenvProvider_ExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	input := "# Service credentials\nexport SERVICE_USERNAME=alice # set by hand\n\nHOST=example.com\nSERVICE_PASSWORD=\"${HOST}-secret\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(input), 0640))
+	p := NewDotenvProvider("service", DotenvOptions{Path: path})
+
+	t.Run("Reads interpolated values", func(t *testing.T) {
+		user, err := p.Get("username")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user)
+
+		secret, err := p.Get("password")
+		assert.NoError(t, err)
+		assert.Equal(t, "example.com-secret", secret)
+	})
+
+	t.Run("Writes keep comments, ordering and permissions", func(t *testing.T) {
+		require.NoError(t, p.Set("username", "bob"))
+		require.NoError(t, p.Set("token", "abc"))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "# Service credentials\nexport SERVICE_USERNAME=bob\n\nHOST=example.com\nSERVICE_PASSWORD=\"${HOST}-secret\"\nSERVICE_TOKEN=abc\n", string(data))
+
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+		}
+	})
+
+	t.Run("Manager reads the identity", func(t *testing.T) {
+		identity, err := NewManagerWithProvider(p).Get()
+		assert.NoError(t, err)
+		assert.Equal(t, Identity{User: "bob", Secret: "example.com-secret"}, identity)
+	})
+
+	t.Run("Environment interpolation can be disabled", func(t *testing.T) {
+		t.Setenv("CRED_TEST_REGION", "eu")
+		require.NoError(t, os.WriteFile(path, []byte("SERVICE_REGION=${CRED_TEST_REGION:-none}\n"), 0600))
+
+		value, err := p.Get("region")
+		assert.NoError(t, err)
+		assert.Equal(t, "eu", value)
+
+		isolated := NewDotenvProvider("service", DotenvOptions{Path: path, IgnoreEnv: true})
+		value, err = isolated.Get("region")
+		assert.NoError(t, err)
+		assert.Equal(t, "none", value)
+	})
+
+	t.Run("Syntax errors are reported", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("SERVICE_USERNAME=\"unterminated\n"), 0600))
+
+		_, err := p.Get("username")
+		assert.ErrorIs(t, err, ErrDotenvSyntax)
+		assert.ErrorIs(t, p.Set("username", "value"), ErrDotenvSyntax)
+	})
+}
@@ -0,0 +1,68 @@
+package cred
+
+// fileProvider implements IdentityProvider using an encrypted vault file
+type fileProvider struct {
+	credLabel string
+	vault     *vault
+}
+
+// NewFileProvider creates a new encrypted vault file based identity provider
+func NewFileProvider(credLabel string, opts VaultOptions) IdentityProvider {
+	return &fileProvider{
+		credLabel: credLabel,
+		vault:     newVault(opts),
+	}
+}
+
+// Set stores a key-value pair in the vault
+func (p *fileProvider) Set(key, value string) error {
+	return p.vault.update(func(entries vaultEntries) bool {
+		// Create the entries of the label if needed
+		if entries[p.credLabel] == nil {
+			entries[p.credLabel] = map[string]string{}
+		}
+
+		// Store the value
+		entries[p.credLabel][key] = value
+		return true
+	})
+}
+
+// Get retrieves a value for the given key from the vault
+func (p *fileProvider) Get(key string) (string, error) {
+	var value string
+	var found bool
+	if err := p.vault.read(func(entries vaultEntries) {
+		value, found = entries[p.credLabel][key]
+	}); err != nil {
+		return "", err
+	}
+
+	// Return the value if found
+	if !found {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Delete removes a key-value pair from the vault (deleting a missing key is not an error)
+func (p *fileProvider) Delete(key string) error {
+	return p.vault.update(func(entries vaultEntries) bool {
+		// Skip saving if there is nothing to delete
+		if _, ok := entries[p.credLabel][key]; !ok {
+			return false
+		}
+
+		// Remove the value, and the label once empty
+		delete(entries[p.credLabel], key)
+		if len(entries[p.credLabel]) == 0 {
+			delete(entries, p.credLabel)
+		}
+		return true
+	})
+}
+
+// CredLabel returns the label for the provider
+func (p *fileProvider) CredLabel() string {
+	return p.credLabel
+}
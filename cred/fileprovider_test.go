@@ -0,0 +1,287 @@
+package cred
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/r3dpixel/toolkit/bytex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVaultOptions returns vault options in a temporary directory, with a fast key derivation
+func testVaultOptions(t *testing.T) VaultOptions {
+	return VaultOptions{
+		Path:       filepath.Join(t.TempDir(), "credentials.vault"),
+		Passphrase: "correct horse battery staple",
+		KDF:        KDFParams{Time: 1, Memory: 64 * bytex.KiB, Threads: 1},
+	}
+}
+
+func TestFileProvider_Lifecycle(t *testing.T) {
+	credLabel := fmt.Sprintf("cred-test-%s", t.Name())
+	key := "test-key"
+	value := "s3cr3t-p@ssw0rd!"
+	p := NewFileProvider(credLabel, testVaultOptions(t))
+
+	t.Run("Get non-existent value fails", func(t *testing.T) {
+		_, err := p.Get(key)
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Set and Get successfully", func(t *testing.T) {
+		require.NoError(t, p.Set(key, value))
+
+		retrieved, err := p.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, value, retrieved)
+	})
+
+	t.Run("Overwrite existing value", func(t *testing.T) {
+		require.NoError(t, p.Set(key, "new-value"))
+
+		retrieved, err := p.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-value", retrieved)
+	})
+
+	t.Run("Delete and verify", func(t *testing.T) {
+		require.NoError(t, p.Delete(key))
+
+		_, err := p.Get(key)
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Delete non-existent value succeeds", func(t *testing.T) {
+		assert.NoError(t, p.Delete(key))
+	})
+
+	t.Run("CredLabel", func(t *testing.T) {
+		assert.Equal(t, credLabel, p.CredLabel())
+	})
+}
+
+func TestFileProvider_Persistence(t *testing.T) {
+	opts := testVaultOptions(t)
+
+	t.Run("Values are shared between providers and labels are isolated", func(t *testing.T) {
+		first := NewFileProvider("first", opts)
+		second := NewFileProvider("second", opts)
+		require.NoError(t, first.Set("user", "alice"))
+		require.NoError(t, second.Set("user", "bob"))
+
+		reopened := NewFileProvider("first", opts)
+		value, err := reopened.Get("user")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", value)
+
+		value, err = second.Get("user")
+		assert.NoError(t, err)
+		assert.Equal(t, "bob", value)
+	})
+
+	t.Run("File is encrypted", func(t *testing.T) {
+		data, err := os.ReadFile(opts.Path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "alice")
+		assert.NotContains(t, string(data), "first")
+	})
+
+	t.Run("File permissions are 0600", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("permissions are not supported on windows")
+		}
+		info, err := os.Stat(opts.Path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("Existing vault keeps its key derivation parameters", func(t *testing.T) {
+		changed := opts
+		changed.KDF = KDFParams{Time: 2, Memory: 128 * bytex.KiB, Threads: 2}
+		p := NewFileProvider("first", changed)
+		require.NoError(t, p.Set("token", "abc"))
+
+		value, err := NewFileProvider("first", opts).Get("token")
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", value)
+	})
+}
+
+func TestFileProvider_InvalidKDF(t *testing.T) {
+	for _, kdf := range []KDFParams{
+		{Time: 101, Memory: 64 * bytex.KiB, Threads: 1},
+		{Time: 1, Memory: 2 * bytex.GiB, Threads: 1},
+		{Time: 1, Memory: 8 * bytex.KiB, Threads: 4},
+	} {
+		t.Run(fmt.Sprintf("%+v", kdf), func(t *testing.T) {
+			// A new vault is not written with parameters it could not be read with
+			opts := testVaultOptions(t)
+			opts.KDF = kdf
+			assert.ErrorIs(t, NewFileProvider("label", opts).Set("key", "value"), ErrInvalidKDF)
+			_, err := os.Stat(opts.Path)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+
+			// An existing vault keeps its own parameters, so it is still writable and readable
+			valid := testVaultOptions(t)
+			require.NoError(t, NewFileProvider("other", valid).Set("key", "value"))
+			valid.KDF = kdf
+			require.NoError(t, NewFileProvider("label", valid).Set("key", "value"))
+			value, err := NewFileProvider("other", valid).Get("key")
+			assert.NoError(t, err)
+			assert.Equal(t, "value", value)
+		})
+	}
+}
+
+func TestFileProvider_Key(t *testing.T) {
+	t.Run("Wrong passphrase fails", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		require.NoError(t, NewFileProvider("label", opts).Set("key", "value"))
+
+		opts.Passphrase = "wrong"
+		_, err := NewFileProvider("label", opts).Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+		assert.ErrorIs(t, NewFileProvider("label", opts).Set("key", "other"), ErrVaultCorrupted)
+	})
+
+	t.Run("Missing key fails", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		opts.Passphrase = ""
+		assert.ErrorIs(t, NewFileProvider("label", opts).Set("key", "value"), ErrVaultKeyMissing)
+	})
+
+	t.Run("Key file takes precedence over passphrase", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		opts.KeyFile = filepath.Join(t.TempDir(), "vault.key")
+		require.NoError(t, os.WriteFile(opts.KeyFile, []byte("key-file-contents\n"), 0600))
+		require.NoError(t, NewFileProvider("label", opts).Set("key", "value"))
+
+		// The trailing newline of the key file is ignored
+		byFile := opts
+		byFile.Passphrase = ""
+		require.NoError(t, os.WriteFile(opts.KeyFile, []byte("key-file-contents"), 0600))
+		value, err := NewFileProvider("label", byFile).Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		byPassphrase := opts
+		byPassphrase.KeyFile = ""
+		_, err = NewFileProvider("label", byPassphrase).Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+	})
+
+	t.Run("Binary key files are used as is", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		opts.Passphrase = ""
+		opts.KeyFile = filepath.Join(t.TempDir(), "vault.key")
+		require.NoError(t, os.WriteFile(opts.KeyFile, []byte("\x00\xffkey\n"), 0600))
+		require.NoError(t, NewFileProvider("label", opts).Set("key", "value"))
+
+		// Removing the trailing whitespace byte changes the key
+		require.NoError(t, os.WriteFile(opts.KeyFile, []byte("\x00\xffkey"), 0600))
+		_, err := NewFileProvider("label", opts).Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+	})
+
+	t.Run("Missing key file fails", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		opts.KeyFile = filepath.Join(t.TempDir(), "missing.key")
+		assert.ErrorIs(t, NewFileProvider("label", opts).Set("key", "value"), os.ErrNotExist)
+	})
+}
+
+func TestFileProvider_Corrupted(t *testing.T) {
+	t.Run("Tampered ciphertext fails", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		p := NewFileProvider("label", opts)
+		require.NoError(t, p.Set("key", "value"))
+
+		data, err := os.ReadFile(opts.Path)
+		require.NoError(t, err)
+		data[len(data)-1] ^= 0xFF
+		require.NoError(t, os.WriteFile(opts.Path, data, 0600))
+
+		_, err = p.Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+	})
+
+	t.Run("Tampered header fails", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		p := NewFileProvider("label", opts)
+		require.NoError(t, p.Set("key", "value"))
+
+		data, err := os.ReadFile(opts.Path)
+		require.NoError(t, err)
+		data[len(vaultMagic)+len("\x01\x01")+3] ^= 0x01 // Lowest byte of the time parameter
+		require.NoError(t, os.WriteFile(opts.Path, data, 0600))
+
+		_, err = p.Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+	})
+
+	t.Run("Tampered memory parameter is rejected before deriving", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		p := NewFileProvider("label", opts)
+		require.NoError(t, p.Set("key", "value"))
+
+		data, err := os.ReadFile(opts.Path)
+		require.NoError(t, err)
+		offset := len(vaultMagic) + len("\x01\x01") + 4 // Memory parameter (KiB), after the time parameter
+		binary.BigEndian.PutUint32(data[offset:], uint32(2*bytex.GiB/bytex.KiB))
+		require.NoError(t, os.WriteFile(opts.Path, data, 0600))
+
+		_, err = p.Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+		assert.ErrorIs(t, err, ErrInvalidKDF)
+	})
+
+	t.Run("Other files are rejected", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		require.NoError(t, os.WriteFile(opts.Path, []byte("not a vault at all"), 0600))
+
+		_, err := NewFileProvider("label", opts).Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+	})
+
+	t.Run("Truncated files are rejected", func(t *testing.T) {
+		opts := testVaultOptions(t)
+		require.NoError(t, os.WriteFile(opts.Path, []byte(vaultMagic), 0600))
+
+		_, err := NewFileProvider("label", opts).Get("key")
+		assert.ErrorIs(t, err, ErrVaultCorrupted)
+	})
+}
+
+func TestFileProvider_Concurrency(t *testing.T) {
+	opts := testVaultOptions(t)
+	const writers = 8
+	const keysPerWriter = 5
+
+	// Each writer has its own provider, as separate processes would
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Go(func() {
+			p := NewFileProvider("concurrent", opts)
+			for j := range keysPerWriter {
+				assert.NoError(t, p.Set(fmt.Sprintf("key-%d-%d", i, j), fmt.Sprintf("value-%d-%d", i, j)))
+			}
+		})
+	}
+	wg.Wait()
+
+	// No update is lost
+	p := NewFileProvider("concurrent", opts)
+	for i := range writers {
+		for j := range keysPerWriter {
+			value, err := p.Get(fmt.Sprintf("key-%d-%d", i, j))
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("value-%d-%d", i, j), value)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package cred
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+)
+
+var (
+	ErrHelperNotConfigured = errors.New("credential helper command not configured")
+	ErrHelperFailed        = errors.New("credential helper failed")
+	ErrHelperProtocol      = errors.New("invalid credential helper message")
+)
+
+const (
+	helperGet   = "get"
+	helperStore = "store"
+	helperErase = "erase"
+
+	helperLabel = "label"
+	helperKey   = "key"
+	helperValue = "value"
+
+	defaultHelperTimeout = 10 * time.Second
+	helperWaitDelay      = time.Second
+	helperStderrLimit    = 4096
+)
+
+// HelperOptions configures the external credential helper provider
+type HelperOptions struct {
+	Command []string      // Helper program and its arguments (the operation get, store or erase is appended)
+	Timeout time.Duration // Timeout of each invocation of the helper (default 10s)
+	Env     []string      // Additional environment variables of the helper (KEY=VALUE)
+	Dir     string        // Working directory of the helper (default the current directory)
+}
+
+// helperProvider implements IdentityProvider by delegating to an external credential helper program
+type helperProvider struct {
+	credLabel string
+	opts      HelperOptions
+}
+
+// NewHelperProvider creates a new identity provider delegating to an external helper, git-credential style.
+// The helper is run with the operation (get, store or erase) as last argument and receives key=value lines on
+// stdin, ended by a blank line: label and key, plus value for store. For get, it writes value=<secret> to stdout
+// (nothing if the key is missing). Erasing a missing key must succeed. A non-zero exit status is a failure.
+func NewHelperProvider(credLabel string, opts HelperOptions) IdentityProvider {
+	// Set default values if needed
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultHelperTimeout
+	}
+
+	// Create the provider
+	return &helperProvider{
+		credLabel: credLabel,
+		opts:      opts,
+	}
+}
+
+// Set stores a key-value pair through the helper
+func (p *helperProvider) Set(key, value string) error {
+	_, err := p.run(helperStore, key, &value)
+	return err
+}
+
+// Get retrieves a value for the given key through the helper
+func (p *helperProvider) Get(key string) (string, error) {
+	// Run the helper
+	attributes, err := p.run(helperGet, key, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Return the value if found
+	value, ok := attributes[helperValue]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Delete removes a key-value pair through the helper
+func (p *helperProvider) Delete(key string) error {
+	_, err := p.run(helperErase, key, nil)
+	return err
+}
+
+// CredLabel returns the label for the provider
+func (p *helperProvider) CredLabel() string {
+	return p.credLabel
+}
+
+// run runs the helper for the operation (sending the value if not nil) and returns the attributes it wrote to stdout
+func (p *helperProvider) run(operation, key string, value *string) (map[string]string, error) {
+	if len(p.opts.Command) == 0 {
+		return nil, ErrHelperNotConfigured
+	}
+
+	// Write the request
+	var stdin bytes.Buffer
+	if err := writeHelperAttribute(&stdin, helperLabel, p.credLabel); err != nil {
+		return nil, p.error(operation, key, err, "")
+	}
+	if err := writeHelperAttribute(&stdin, helperKey, key); err != nil {
+		return nil, p.error(operation, key, err, "")
+	}
+	if value != nil {
+		if err := writeHelperAttribute(&stdin, helperValue, *value); err != nil {
+			return nil, p.error(operation, key, err, "")
+		}
+	}
+	stdin.WriteByte('\n')
+
+	// Run the helper with the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+	args := append(p.opts.Command[1:len(p.opts.Command):len(p.opts.Command)], operation)
+	cmd := exec.CommandContext(ctx, p.opts.Command[0], args...)
+	cmd.Env = append(os.Environ(), p.opts.Env...)
+	cmd.Dir = p.opts.Dir
+	cmd.WaitDelay = helperWaitDelay
+	cmd.Stdin = &stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+		return nil, p.error(operation, key, fmt.Errorf("%w: %w", ErrHelperFailed, err), stderr.String())
+	}
+
+	// Parse the response
+	attributes, err := parseHelperAttributes(&stdout)
+	if err != nil {
+		return nil, p.error(operation, key, err, stderr.String())
+	}
+	return attributes, nil
+}
+
+// error wraps the error with the label, key, command and captured stderr
+func (p *helperProvider) error(operation, key string, err error, stderr string) error {
+	// Keep the end of long outputs (where the cause usually is)
+	stderr = strings.TrimSpace(stderr)
+	if len(stderr) > helperStderrLimit {
+		stderr = stderr[len(stderr)-helperStderrLimit:]
+	}
+
+	// Wrap the error
+	traced := trace.Error().
+		Msg(operation).
+		Field(trace.LABEL, p.credLabel).
+		Field(trace.KEY, key).
+		Field(trace.COMMAND, p.opts.Command[0])
+	if stderr != "" {
+		traced.Field(trace.STDERR, stderr)
+	}
+	return traced.Wrap(err)
+}
+
+// writeHelperAttribute writes a key=value line (values cannot contain newlines or NUL characters)
+func writeHelperAttribute(b *bytes.Buffer, name, value string) error {
+	if strings.ContainsAny(value, "\n\x00") {
+		return fmt.Errorf("%w: %s contains a newline or NUL character", ErrHelperProtocol, name)
+	}
+	b.WriteString(name)
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte('\n')
+	return nil
+}
+
+// parseHelperAttributes parses key=value lines, up to a blank line or the end of the output
+func parseHelperAttributes(stdout *bytes.Buffer) (map[string]string, error) {
+	attributes := map[string]string{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: line without '=': %q", ErrHelperProtocol, line)
+		}
+		attributes[name] = value
+	}
+	return attributes, scanner.Err()
+}
@@ -0,0 +1,179 @@
+package cred
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	helperProcessEnv = "CRED_TEST_HELPER_PROCESS"
+	helperStoreEnv   = "CRED_TEST_HELPER_STORE"
+	helperModeEnv    = "CRED_TEST_HELPER_MODE"
+)
+
+// TestHelperProcess is not a test, it is the credential helper run by the helper provider tests
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(helperProcessEnv) != "1" {
+		t.Skip("run as a credential helper by the helper provider tests")
+	}
+	os.Exit(runTestHelper(os.Args[len(os.Args)-1]))
+}
+
+// runTestHelper implements a credential helper storing the values in a JSON file
+func runTestHelper(operation string) int {
+	// Simulate failures
+	switch os.Getenv(helperModeEnv) {
+	case "fail":
+		fmt.Fprintln(os.Stderr, "vault sealed: unseal it first")
+		return 1
+	case "hang":
+		time.Sleep(time.Minute)
+	case "garbage":
+		fmt.Println("not an attribute")
+		return 0
+	}
+
+	// Read the request
+	attributes := map[string]string{}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() && scanner.Text() != "" {
+		name, value, _ := strings.Cut(scanner.Text(), "=")
+		attributes[name] = value
+	}
+
+	// Load the store
+	path := os.Getenv(helperStoreEnv)
+	store := map[string]string{}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &store)
+	}
+	id := attributes["label"] + "/" + attributes["key"]
+
+	// Apply the operation
+	switch operation {
+	case "get":
+		if value, ok := store[id]; ok {
+			fmt.Printf("value=%s\n\n", value)
+		}
+		return 0
+	case "store":
+		store[id] = attributes["value"]
+	case "erase":
+		delete(store, id)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown operation %s\n", operation)
+		return 2
+	}
+
+	// Save the store
+	data, _ := json.Marshal(store)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// testHelperOptions returns options running TestHelperProcess as the helper, in the given mode
+func testHelperOptions(t *testing.T, mode string) HelperOptions {
+	return HelperOptions{
+		Command: []string{os.Args[0], "-test.run=^TestHelperProcess$", "--"},
+		Env: []string{
+			helperProcessEnv + "=1",
+			helperStoreEnv + "=" + filepath.Join(t.TempDir(), "store.json"),
+			helperModeEnv + "=" + mode,
+			"GORACE=atexit_sleep_ms=0",
+		},
+	}
+}
+
+func TestHelperProvider_Lifecycle(t *testing.T) {
+	credLabel := fmt.Sprintf("cred-test-%s", t.Name())
+	p := NewHelperProvider(credLabel, testHelperOptions(t, ""))
+
+	t.Run("Get non-existent value fails", func(t *testing.T) {
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Set and Get successfully", func(t *testing.T) {
+		require.NoError(t, p.Set("key", "s3cr3t=p@ss w0rd"))
+
+		value, err := p.Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cr3t=p@ss w0rd", value)
+	})
+
+	t.Run("Delete and verify", func(t *testing.T) {
+		require.NoError(t, p.Delete("key"))
+		assert.NoError(t, p.Delete("key"), "Deleting a non-existent value should not return an error")
+
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Manager lifecycle", func(t *testing.T) {
+		testManagerLifecycle(t, NewManagerWithProvider(p), ErrSecretNotFound)
+	})
+
+	t.Run("CredLabel", func(t *testing.T) {
+		assert.Equal(t, credLabel, p.CredLabel())
+	})
+}
+
+func TestHelperProvider_Errors(t *testing.T) {
+	t.Run("Missing command", func(t *testing.T) {
+		_, err := NewHelperProvider("label", HelperOptions{}).Get("key")
+		assert.ErrorIs(t, err, ErrHelperNotConfigured)
+	})
+
+	t.Run("Failure captures stderr", func(t *testing.T) {
+		opts := testHelperOptions(t, "fail")
+		_, err := NewHelperProvider("label", opts).Get("key")
+		assert.ErrorIs(t, err, ErrHelperFailed)
+		assert.False(t, IsNotFound(err))
+
+		var tracedErr *trace.Err
+		require.True(t, errors.As(err, &tracedErr))
+		assert.Contains(t, tracedErr.GetField(trace.STDERR), "vault sealed: unseal it first")
+		assert.Equal(t, "label", tracedErr.GetField(trace.LABEL))
+		assert.Equal(t, "key", tracedErr.GetField(trace.KEY))
+		assert.Equal(t, opts.Command[0], tracedErr.GetField(trace.COMMAND))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		opts := testHelperOptions(t, "hang")
+		opts.Timeout = 200 * time.Millisecond
+
+		start := time.Now()
+		err := NewHelperProvider("label", opts).Set("key", "value")
+		assert.ErrorIs(t, err, ErrHelperFailed)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		_, err := NewHelperProvider("label", testHelperOptions(t, "garbage")).Get("key")
+		assert.ErrorIs(t, err, ErrHelperProtocol)
+	})
+
+	t.Run("Values with newlines are rejected", func(t *testing.T) {
+		err := NewHelperProvider("label", testHelperOptions(t, "")).Set("key", "multi\nline")
+		assert.ErrorIs(t, err, ErrHelperProtocol)
+	})
+
+	t.Run("Missing program", func(t *testing.T) {
+		err := NewHelperProvider("label", HelperOptions{Command: []string{filepath.Join(t.TempDir(), "missing")}}).Delete("key")
+		assert.ErrorIs(t, err, ErrHelperFailed)
+	})
+}
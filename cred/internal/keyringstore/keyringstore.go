@@ -0,0 +1,55 @@
+// Package keyringstore holds the keyring backend used by cred, so credtest can swap it without exporting a setter
+package keyringstore
+
+import (
+	"sync/atomic"
+
+	"github.com/zalando/go-keyring"
+)
+
+// holder wraps a backend (atomic.Pointer needs a concrete type)
+type holder struct {
+	keyring.Keyring
+}
+
+// current is the swapped backend (nil for the OS keyring)
+var current atomic.Pointer[holder]
+
+// Backend returns the current backend (safe for concurrent use)
+func Backend() keyring.Keyring {
+	if h := current.Load(); h != nil {
+		return h.Keyring
+	}
+	return OS{}
+}
+
+// Swap replaces the backend, returning the previous one (safe for concurrent use)
+func Swap(backend keyring.Keyring) keyring.Keyring {
+	if previous := current.Swap(&holder{backend}); previous != nil {
+		return previous.Keyring
+	}
+	return OS{}
+}
+
+// OS implements keyring.Keyring using the OS keyring
+type OS struct{}
+
+// Get retrieves a value from the OS keyring
+func (OS) Get(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+// Set stores a value in the OS keyring
+func (OS) Set(service, user, password string) error {
+	return keyring.Set(service, user, password)
+}
+
+// Delete removes a value from the OS keyring
+func (OS) Delete(service, user string) error {
+	return keyring.Delete(service, user)
+}
+
+// DeleteAll removes every value of the service from the OS keyring
+func (OS) DeleteAll(service string) error {
+	return keyring.DeleteAll(service)
+}
@@ -3,22 +3,23 @@ package cred
 import (
 	"errors"
 
+	"github.com/r3dpixel/toolkit/cred/internal/keyringstore"
 	"github.com/zalando/go-keyring"
 )
 
 // FromKeyRing retrieves a value from the OS keyring by label and key.
 func FromKeyRing(credLabel, key string) (string, error) {
-	return keyring.Get(credLabel, key)
+	return keyringstore.Backend().Get(credLabel, key)
 }
 
 // ToKeyRing stores a key-value pair in the OS keyring under the given label.
 func ToKeyRing(credLabel, key, value string) error {
-	return keyring.Set(credLabel, key, value)
+	return keyringstore.Backend().Set(credLabel, key, value)
 }
 
 // DeleteKeyRing removes a key from the OS keyring, ignoring not found errors.
 func DeleteKeyRing(credLabel, key string) error {
-	err := keyring.Delete(credLabel, key)
+	err := keyringstore.Backend().Delete(credLabel, key)
 
 	if errors.Is(err, keyring.ErrNotFound) {
 		return nil
@@ -11,17 +11,56 @@ type Mode byte
 const (
 	KeyRing Mode = iota // IdentityManager will use the OS keyring
 	Env                 // IdentityManager will use the environment through environment variables
+	File                // IdentityManager will use an encrypted vault file
+	Memory              // IdentityManager will use an in-memory store (intended for tests)
+	Dotenv              // IdentityManager will use a dotenv file
+	Netrc               // IdentityManager will use a netrc file (the label being the machine name)
+	Helper              // IdentityManager will use an external credential helper program
 )
 
+// ManagerOptions configures the identity provider of a Mode
+type ManagerOptions struct {
+	Vault  VaultOptions  // Options of the vault file (File mode)
+	Dotenv DotenvOptions // Options of the dotenv file (Dotenv mode)
+	Netrc  NetrcOptions  // Options of the netrc file (Netrc mode)
+	Helper HelperOptions // Options of the credential helper (Helper mode)
+}
+
 // manager internally uses IdentityProvider to read/write credentials
 type manager struct {
 	provider IdentityProvider
 }
 
 // NewManager creates a new identity manager with the specified label and Mode
-func NewManager(credLabel string, mode Mode) IdentityManager {
+func NewManager(credLabel string, mode Mode, opts ...ManagerOptions) IdentityManager {
+	return NewSecureManager(credLabel, mode, opts...)
+}
+
+// NewManagerWithProvider creates a new identity manager using the specified provider
+func NewManagerWithProvider(provider IdentityProvider) IdentityManager {
+	return NewSecureManagerWithProvider(provider)
+}
+
+// NewSecureManager creates a new identity manager with the specified label and Mode,
+// including the variants using redacted secrets and TOTP seeds
+func NewSecureManager(credLabel string, mode Mode, opts ...ManagerOptions) SecureIdentityManager {
+	// Use the provided options or the defaults
+	var opt ManagerOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	// Create the manager
+	return &manager{
+		provider: getProvider(credLabel, mode, opt),
+	}
+}
+
+// NewSecureManagerWithProvider creates a new identity manager using the specified provider,
+// including the variants using redacted secrets and TOTP seeds
+func NewSecureManagerWithProvider(provider IdentityProvider) SecureIdentityManager {
 	return &manager{
-		provider: getProvider(credLabel, mode),
+		provider: provider,
 	}
 }
 
@@ -93,16 +132,85 @@ func (m *manager) Delete() error {
 	return m.provider.Delete(secretKey)
 }
 
+// SetAllSecure sets both user and secret credentials from the provided identity
+func (m *manager) SetAllSecure(identity SecureIdentity) error {
+	if err := m.provider.Set(userKey, identity.User); err != nil {
+		return err
+	}
+	return m.provider.Set(secretKey, identity.Secret.Reveal())
+}
+
+// SetSecretSecure sets the secret credential
+func (m *manager) SetSecretSecure(secret *Secret) error {
+	return m.provider.Set(secretKey, secret.Reveal())
+}
+
+// GetSecure retrieves both user and secret credentials and returns them as a SecureIdentity
+func (m *manager) GetSecure() (SecureIdentity, error) {
+	user, err := m.provider.Get(userKey)
+	if err != nil {
+		return SecureIdentity{}, err
+	}
+	secret, err := m.GetSecretSecure()
+	if err != nil {
+		return SecureIdentity{}, err
+	}
+
+	return SecureIdentity{
+		User:   user,
+		Secret: secret,
+	}, nil
+}
+
+// GetSecretSecure retrieves the password credential as a Secret
+func (m *manager) GetSecretSecure() (*Secret, error) {
+	secret, err := m.provider.Get(secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecret(secret), nil
+}
+
+// SetTOTP stores the TOTP seed next to the identity
+func (m *manager) SetTOTP(totp TOTP) error {
+	return SetTOTP(m.provider, totp)
+}
+
+// GetTOTP retrieves the TOTP seed stored next to the identity
+func (m *manager) GetTOTP() (TOTP, error) {
+	return GetTOTP(m.provider)
+}
+
+// TOTPCode returns the current code of the TOTP seed stored next to the identity
+func (m *manager) TOTPCode() (string, error) {
+	return TOTPCode(m.provider)
+}
+
+// DeleteTOTP removes the TOTP seed stored next to the identity
+func (m *manager) DeleteTOTP() error {
+	return DeleteTOTP(m.provider)
+}
+
 // CredLabel returns the label of the identity provider
 func (m *manager) CredLabel() string {
 	return m.provider.CredLabel()
 }
 
 // getProvider returns the appropriate identity provider based on the specified mode
-func getProvider(label string, mode Mode) IdentityProvider {
+func getProvider(label string, mode Mode, opts ManagerOptions) IdentityProvider {
 	switch mode {
 	case Env:
 		return NewEnvProvider(label)
+	case File:
+		return NewFileProvider(label, opts.Vault)
+	case Memory:
+		return NewMemoryProvider(label)
+	case Dotenv:
+		return NewDotenvProvider(label, opts.Dotenv)
+	case Netrc:
+		return NewNetrcProvider(label, opts.Netrc)
+	case Helper:
+		return NewHelperProvider(label, opts.Helper)
 	case KeyRing:
 		return NewKeyProvider(label)
 	}
@@ -3,6 +3,7 @@ package cred
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -120,6 +121,7 @@ func TestManager(t *testing.T) {
 	testModes := []struct {
 		name        string
 		mode        Mode
+		opts        ManagerOptions
 		notFoundErr error
 	}{
 		{
@@ -132,6 +134,35 @@ func TestManager(t *testing.T) {
 			mode:        Env,
 			notFoundErr: ErrEnvVarNotFound,
 		},
+		{
+			name:        "File Mode",
+			mode:        File,
+			opts:        ManagerOptions{Vault: testVaultOptions(t)},
+			notFoundErr: ErrSecretNotFound,
+		},
+		{
+			name:        "Memory Mode",
+			mode:        Memory,
+			notFoundErr: ErrSecretNotFound,
+		},
+		{
+			name:        "Dotenv Mode",
+			mode:        Dotenv,
+			opts:        ManagerOptions{Dotenv: DotenvOptions{Path: filepath.Join(t.TempDir(), ".env")}},
+			notFoundErr: ErrEnvVarNotFound,
+		},
+		{
+			name:        "Netrc Mode",
+			mode:        Netrc,
+			opts:        ManagerOptions{Netrc: NetrcOptions{Path: filepath.Join(t.TempDir(), ".netrc")}},
+			notFoundErr: ErrSecretNotFound,
+		},
+		{
+			name:        "Helper Mode",
+			mode:        Helper,
+			opts:        ManagerOptions{Helper: testHelperOptions(t, "")},
+			notFoundErr: ErrSecretNotFound,
+		},
 	}
 
 	testCases := []struct {
@@ -148,7 +179,7 @@ func TestManager(t *testing.T) {
 	for _, mode := range testModes {
 		t.Run(mode.name, func(t *testing.T) {
 			credLabel := fmt.Sprintf("cred-test-%s", t.Name())
-			m := NewManager(credLabel, mode.mode)
+			m := NewManager(credLabel, mode.mode, mode.opts)
 			t.Cleanup(func() {
 				_ = m.Delete()
 			})
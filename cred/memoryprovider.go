@@ -0,0 +1,152 @@
+package cred
+
+import (
+	"maps"
+	"sync"
+)
+
+// Operation an operation of an IdentityProvider (used to inject failures)
+type Operation byte
+
+const (
+	OpAny    Operation = iota // Matches every operation
+	OpGet                     // Matches Get
+	OpSet                     // Matches Set
+	OpDelete                  // Matches Delete
+)
+
+// AnyKey matches every key when injecting failures
+const AnyKey = ""
+
+// failureKey identifies an injected failure
+type failureKey struct {
+	op  Operation
+	key string
+}
+
+// MemoryProvider implements IdentityProvider in memory, with inspectable contents and failure injection.
+// It is safe for concurrent use, intended for tests of code depending on an IdentityProvider or IdentityManager.
+type MemoryProvider struct {
+	mu        sync.RWMutex
+	credLabel string
+	values    map[string]string
+	failures  map[failureKey]error
+}
+
+// NewMemoryProvider creates a new empty in-memory identity provider
+func NewMemoryProvider(credLabel string) *MemoryProvider {
+	return &MemoryProvider{
+		credLabel: credLabel,
+		values:    map[string]string{},
+		failures:  map[failureKey]error{},
+	}
+}
+
+// Set stores a key-value pair in memory
+func (p *MemoryProvider) Set(key, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Return the injected failure if any
+	if err := p.failure(OpSet, key); err != nil {
+		return err
+	}
+
+	// Store the value
+	p.values[key] = value
+	return nil
+}
+
+// Get retrieves a value for the given key from memory
+func (p *MemoryProvider) Get(key string) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// Return the injected failure if any
+	if err := p.failure(OpGet, key); err != nil {
+		return "", err
+	}
+
+	// Return the value if found
+	value, ok := p.values[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Delete removes a key-value pair from memory (deleting a missing key is not an error)
+func (p *MemoryProvider) Delete(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Return the injected failure if any
+	if err := p.failure(OpDelete, key); err != nil {
+		return err
+	}
+
+	// Remove the value
+	delete(p.values, key)
+	return nil
+}
+
+// CredLabel returns the label for the provider
+func (p *MemoryProvider) CredLabel() string {
+	return p.credLabel
+}
+
+// Values returns a copy of the stored key-value pairs
+func (p *MemoryProvider) Values() map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return maps.Clone(p.values)
+}
+
+// Has returns true if a value is stored for the key
+func (p *MemoryProvider) Has(key string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.values[key]
+	return ok
+}
+
+// Fail makes the operation (or OpAny) on the key (or AnyKey) return err, until cleared (a nil err clears it)
+func (p *MemoryProvider) Fail(op Operation, key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		delete(p.failures, failureKey{op: op, key: key})
+		return
+	}
+	p.failures[failureKey{op: op, key: key}] = err
+}
+
+// ClearFailures removes all injected failures
+func (p *MemoryProvider) ClearFailures() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clear(p.failures)
+}
+
+// Reset removes all stored values and injected failures
+func (p *MemoryProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clear(p.values)
+	clear(p.failures)
+}
+
+// failure returns the injected failure for the operation on the key, the most specific one first
+func (p *MemoryProvider) failure(op Operation, key string) error {
+	for _, k := range [...]failureKey{{op, key}, {op, AnyKey}, {OpAny, key}, {OpAny, AnyKey}} {
+		if err, ok := p.failures[k]; ok {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package cred
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryProvider_Lifecycle(t *testing.T) {
+	credLabel := fmt.Sprintf("cred-test-%s", t.Name())
+	p := NewMemoryProvider(credLabel)
+
+	t.Run("Get non-existent value fails", func(t *testing.T) {
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+		assert.False(t, p.Has("key"))
+	})
+
+	t.Run("Set and Get successfully", func(t *testing.T) {
+		require.NoError(t, p.Set("key", "value"))
+
+		value, err := p.Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+		assert.True(t, p.Has("key"))
+	})
+
+	t.Run("Values returns a copy", func(t *testing.T) {
+		values := p.Values()
+		assert.Equal(t, map[string]string{"key": "value"}, values)
+
+		values["key"] = "changed"
+		value, _ := p.Get("key")
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("Delete and verify", func(t *testing.T) {
+		require.NoError(t, p.Delete("key"))
+		assert.NoError(t, p.Delete("key"), "Deleting a non-existent value should not return an error")
+
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("CredLabel", func(t *testing.T) {
+		assert.Equal(t, credLabel, p.CredLabel())
+	})
+}
+
+func TestMemoryProvider_Failures(t *testing.T) {
+	errKey := errors.New("key failure")
+	errOp := errors.New("operation failure")
+	errAny := errors.New("any failure")
+
+	t.Run("Failure for an operation on a key", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		p.Fail(OpSet, "key", errKey)
+
+		assert.ErrorIs(t, p.Set("key", "value"), errKey)
+		assert.NoError(t, p.Set("other", "value"))
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+		assert.False(t, p.Has("key"), "Failed operations should not change the contents")
+	})
+
+	t.Run("Failure for an operation on any key", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		require.NoError(t, p.Set("key", "value"))
+		p.Fail(OpGet, AnyKey, errOp)
+
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, errOp)
+		_, err = p.Get("other")
+		assert.ErrorIs(t, err, errOp)
+		assert.NoError(t, p.Delete("key"))
+	})
+
+	t.Run("Failure for any operation", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		p.Fail(OpAny, AnyKey, errAny)
+
+		assert.ErrorIs(t, p.Set("key", "value"), errAny)
+		_, err := p.Get("key")
+		assert.ErrorIs(t, err, errAny)
+		assert.ErrorIs(t, p.Delete("key"), errAny)
+	})
+
+	t.Run("Most specific failure wins", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		p.Fail(OpAny, AnyKey, errAny)
+		p.Fail(OpDelete, AnyKey, errOp)
+		p.Fail(OpDelete, "key", errKey)
+
+		assert.ErrorIs(t, p.Delete("key"), errKey)
+		assert.ErrorIs(t, p.Delete("other"), errOp)
+		assert.ErrorIs(t, p.Set("key", "value"), errAny)
+	})
+
+	t.Run("Failures are cleared", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		p.Fail(OpSet, "key", errKey)
+		p.Fail(OpGet, "key", errKey)
+
+		p.Fail(OpSet, "key", nil)
+		assert.NoError(t, p.Set("key", "value"))
+
+		p.ClearFailures()
+		value, err := p.Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("Reset removes values and failures", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		require.NoError(t, p.Set("key", "value"))
+		p.Fail(OpAny, AnyKey, errAny)
+
+		p.Reset()
+		assert.Empty(t, p.Values())
+		assert.NoError(t, p.Set("key", "value"))
+	})
+}
+
+func TestMemoryProvider_Manager(t *testing.T) {
+	p := NewMemoryProvider("label")
+	m := NewManagerWithProvider(p)
+	assert.Equal(t, "label", m.CredLabel())
+
+	t.Run("Manager writes are inspectable", func(t *testing.T) {
+		require.NoError(t, m.SetAll(Identity{User: "user", Secret: "secret"}))
+		assert.Equal(t, map[string]string{userKey: "user", secretKey: "secret"}, p.Values())
+	})
+
+	t.Run("Manager surfaces injected failures", func(t *testing.T) {
+		errUnavailable := errors.New("backend unavailable")
+		p.Fail(OpGet, secretKey, errUnavailable)
+		t.Cleanup(p.ClearFailures)
+
+		_, err := m.Get()
+		assert.ErrorIs(t, err, errUnavailable)
+		user, err := m.GetUser()
+		assert.NoError(t, err)
+		assert.Equal(t, "user", user)
+	})
+}
+
+func TestMemoryProvider_Concurrency(t *testing.T) {
+	p := NewMemoryProvider("label")
+
+	var wg sync.WaitGroup
+	const numGoroutines = 10
+
+	for i := range numGoroutines {
+		wg.Go(func() {
+			key := fmt.Sprintf("key-%d", i)
+			_ = p.Set(key, "value")
+			_, _ = p.Get(key)
+			_ = p.Values()
+			p.Fail(OpGet, key, errors.New("failure"))
+			_ = p.Delete(key)
+			_ = p.Set(key, "final")
+		})
+	}
+	wg.Wait()
+
+	assert.Len(t, p.Values(), numGoroutines)
+}
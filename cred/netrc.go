@@ -0,0 +1,346 @@
+package cred
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNetrcSyntax = errors.New("invalid netrc syntax")
+
+const (
+	netrcMachine  = "machine"
+	netrcDefault  = "default"
+	netrcMacdef   = "macdef"
+	netrcLogin    = "login"
+	netrcPassword = "password"
+	netrcAccount  = "account"
+)
+
+// netrcToken is a token of a netrc file and its position
+type netrcToken struct {
+	value      string // Value of the token (unquoted)
+	start, end int    // Position of the token in the file, including the quotes
+}
+
+// netrcField is a keyword-value pair of an entry
+type netrcField struct {
+	keyword, value netrcToken
+}
+
+// netrcEntry is a machine (or default) entry of a netrc file
+type netrcEntry struct {
+	machine   string                // Name of the machine (empty for the default entry)
+	isDefault bool                  // The entry is the default entry
+	start     int                   // Start of the machine (or default) token
+	end       int                   // End of the last token of the entry
+	next      int                   // Start of the next machine, default or macdef token (or the end of the file)
+	fields    map[string]netrcField // Fields by keyword
+}
+
+// has returns true if the entry has the field
+func (e *netrcEntry) has(keyword string) bool {
+	_, ok := e.fields[keyword]
+	return ok
+}
+
+// netrcFile is a parsed netrc file, edited in place to keep its formatting
+type netrcFile struct {
+	text    string
+	entries []*netrcEntry
+}
+
+// parseNetrc parses the entries of a netrc file (macdef bodies and lines starting with '#' are skipped)
+func parseNetrc(text string) (*netrcFile, error) {
+	f := &netrcFile{text: text}
+	s := &netrcScanner{text: text, lineStart: true}
+
+	var entry *netrcEntry
+	closeEntry := func(next int) {
+		if entry != nil {
+			entry.next = next
+		}
+	}
+	for {
+		// Keywords are only recognized in keyword position (values may be keywords, e.g. a "macdef" password)
+		token, ok := s.next()
+		if !ok {
+			break
+		}
+		switch token.value {
+		// Start a new machine entry
+		case netrcMachine:
+			name, ok := s.next()
+			if !ok {
+				return nil, s.missing("missing machine name")
+			}
+			closeEntry(token.start)
+			entry = &netrcEntry{machine: name.value, start: token.start, end: name.end, fields: map[string]netrcField{}}
+			f.entries = append(f.entries, entry)
+
+		// Start the default entry
+		case netrcDefault:
+			closeEntry(token.start)
+			entry = &netrcEntry{isDefault: true, start: token.start, end: token.end, fields: map[string]netrcField{}}
+			f.entries = append(f.entries, entry)
+
+		// Add a field to the current entry
+		case netrcLogin, netrcPassword, netrcAccount:
+			if entry == nil {
+				return nil, fmt.Errorf("%w: %s outside of an entry", ErrNetrcSyntax, token.value)
+			}
+			value, ok := s.next()
+			if !ok {
+				return nil, s.missing(token.value + " without value")
+			}
+			entry.fields[token.value] = netrcField{keyword: token, value: value}
+			entry.end = value.end
+
+		// Macro definitions end the current entry, and their bodies are skipped
+		case netrcMacdef:
+			closeEntry(token.start)
+			entry = nil
+			if _, ok := s.next(); ok {
+				s.skipMacro()
+			}
+
+		// Skip unknown fields with their values (e.g. port)
+		default:
+			if next, ok := s.peek(); entry != nil && ok && !isNetrcKeyword(next.value) {
+				s.next()
+			}
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	closeEntry(len(text))
+	return f, nil
+}
+
+// netrcScanner reads the tokens of a netrc file one at a time (whitespace separated, optionally double-quoted
+// with escapes), so the parser decides where macro bodies start. Errors are sticky.
+type netrcScanner struct {
+	text      string
+	pos       int
+	lineStart bool
+	peeked    *netrcToken
+	err       error
+}
+
+// next returns the next token (false at the end of the file or on error)
+func (s *netrcScanner) next() (netrcToken, bool) {
+	// Return the peeked token first
+	if s.peeked != nil {
+		token := *s.peeked
+		s.peeked = nil
+		return token, true
+	}
+
+	for s.err == nil && s.pos < len(s.text) {
+		c := s.text[s.pos]
+		switch {
+		// Skip whitespace
+		case c == '\n':
+			s.lineStart = true
+			s.pos++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			s.pos++
+
+		// Skip comment lines
+		case c == '#' && s.lineStart:
+			for s.pos < len(s.text) && s.text[s.pos] != '\n' {
+				s.pos++
+			}
+
+		// Parse a quoted token
+		case c == '"':
+			var b strings.Builder
+			start := s.pos
+			for s.pos++; s.pos < len(s.text) && s.text[s.pos] != '"'; s.pos++ {
+				if s.text[s.pos] == '\\' && s.pos+1 < len(s.text) {
+					s.pos++
+				}
+				b.WriteByte(s.text[s.pos])
+			}
+			if s.pos >= len(s.text) {
+				s.err = fmt.Errorf("%w: unterminated quote", ErrNetrcSyntax)
+				return netrcToken{}, false
+			}
+			s.pos++
+			s.lineStart = false
+			return netrcToken{value: b.String(), start: start, end: s.pos}, true
+
+		// Parse a plain token
+		default:
+			start := s.pos
+			for s.pos < len(s.text) && !isNetrcSpace(s.text[s.pos]) {
+				s.pos++
+			}
+			s.lineStart = false
+			return netrcToken{value: s.text[start:s.pos], start: start, end: s.pos}, true
+		}
+	}
+	return netrcToken{}, false
+}
+
+// peek returns the next token without consuming it
+func (s *netrcScanner) peek() (netrcToken, bool) {
+	if s.peeked == nil {
+		token, ok := s.next()
+		if !ok {
+			return token, false
+		}
+		s.peeked = &token
+	}
+	return *s.peeked, true
+}
+
+// skipMacro skips the body of a macro definition, from the end of its name up to an empty line
+func (s *netrcScanner) skipMacro() {
+	if end := strings.Index(s.text[s.pos:], "\n\n"); end >= 0 {
+		s.pos += end + 2
+	} else {
+		s.pos = len(s.text)
+	}
+	s.lineStart = true
+}
+
+// missing returns the scanner error, or a syntax error for a missing token
+func (s *netrcScanner) missing(what string) error {
+	if s.err != nil {
+		return s.err
+	}
+	return fmt.Errorf("%w: %s", ErrNetrcSyntax, what)
+}
+
+// entry returns the entry of the machine (nil if none)
+func (f *netrcFile) entry(machine string) *netrcEntry {
+	for _, entry := range f.entries {
+		if !entry.isDefault && entry.machine == machine {
+			return entry
+		}
+	}
+	return nil
+}
+
+// defaultEntry returns the default entry (nil if none)
+func (f *netrcFile) defaultEntry() *netrcEntry {
+	for _, entry := range f.entries {
+		if entry.isDefault {
+			return entry
+		}
+	}
+	return nil
+}
+
+// set sets the field of the machine, adding the field or the entry if needed
+func (f *netrcFile) set(machine, keyword, value string) error {
+	entry := f.entry(machine)
+	var err error
+	switch {
+	// Replace the value of an existing field
+	case entry != nil && entry.has(keyword):
+		field := entry.fields[keyword]
+		err = f.splice(field.value.start, field.value.end, quoteNetrc(value))
+
+	// Append the field to an existing entry
+	case entry != nil:
+		err = f.splice(entry.end, entry.end, " "+keyword+" "+quoteNetrc(value))
+
+	// Add an entry before the default entry (which must be last), or at the end
+	default:
+		line := netrcMachine + " " + quoteNetrc(machine) + " " + keyword + " " + quoteNetrc(value) + "\n"
+		if def := f.defaultEntry(); def != nil {
+			err = f.splice(def.start, def.start, line)
+		} else {
+			if f.text != "" && !strings.HasSuffix(f.text, "\n") {
+				line = "\n" + line
+			}
+			err = f.splice(len(f.text), len(f.text), line)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Check that the value reads back
+	if entry := f.entry(machine); entry == nil || !entry.has(keyword) || entry.fields[keyword].value.value != value {
+		return fmt.Errorf("%w: %s of %s does not read back after writing", ErrNetrcSyntax, keyword, machine)
+	}
+	return nil
+}
+
+// delete removes the field of the machine (and the entry once empty), returning true if it existed
+func (f *netrcFile) delete(machine, keyword string) (bool, error) {
+	entry := f.entry(machine)
+	if entry == nil || !entry.has(keyword) {
+		return false, nil
+	}
+
+	// Remove the whole entry if it has no other field, with its unknown fields (e.g. port) up to the next entry
+	var err error
+	if len(entry.fields) == 1 {
+		err = f.splice(entry.start, entry.next, "")
+	} else {
+		// Remove the field, with the whitespace preceding it
+		field := entry.fields[keyword]
+		start := field.keyword.start
+		for start > 0 && isNetrcSpace(f.text[start-1]) {
+			start--
+		}
+		err = f.splice(start, field.value.end, "")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Check that the field is gone
+	if entry := f.entry(machine); entry != nil && entry.has(keyword) {
+		return false, fmt.Errorf("%w: %s of %s still reads back after deleting", ErrNetrcSyntax, keyword, machine)
+	}
+	return true, nil
+}
+
+// splice replaces the text between start and end, then parses the file again (keeping the file unchanged on error)
+func (f *netrcFile) splice(start, end int, replacement string) error {
+	parsed, err := parseNetrc(f.text[:start] + replacement + f.text[end:])
+	if err != nil {
+		return err
+	}
+	*f = *parsed
+	return nil
+}
+
+// quoteNetrc returns the value as a netrc token, quoted and escaped if needed (keywords are quoted for readers
+// that recognize them in any position)
+func quoteNetrc(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"\\#") && !isNetrcKeyword(value) {
+		return value
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := range len(value) {
+		if value[i] == '"' || value[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(value[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isNetrcKeyword returns true for the tokens starting an entry or a field
+func isNetrcKeyword(value string) bool {
+	switch value {
+	case netrcMachine, netrcDefault, netrcMacdef, netrcLogin, netrcPassword, netrcAccount:
+		return true
+	}
+	return false
+}
+
+// isNetrcSpace returns true for the characters separating tokens
+func isNetrcSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
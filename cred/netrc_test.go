@@ -0,0 +1,176 @@
+package cred
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetrc = `# Personal machines
+machine example.com
+    login alice
+    password "p@ss word \"quoted\""
+
+machine api.example.com login bob password secret account team port 443
+
+macdef init
+cd /pub
+machine fake.com login mallory
+
+default login anonymous password guest@example.com
+`
+
+func TestParseNetrc(t *testing.T) {
+	f, err := parseNetrc(testNetrc)
+	require.NoError(t, err)
+
+	t.Run("Entries", func(t *testing.T) {
+		require.Len(t, f.entries, 3)
+		assert.Equal(t, "example.com", f.entries[0].machine)
+		assert.Equal(t, "api.example.com", f.entries[1].machine)
+		assert.True(t, f.entries[2].isDefault)
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		entry := f.entry("example.com")
+		require.NotNil(t, entry)
+		assert.Equal(t, "alice", entry.fields[netrcLogin].value.value)
+		assert.Equal(t, `p@ss word "quoted"`, entry.fields[netrcPassword].value.value)
+		assert.False(t, entry.has(netrcAccount))
+
+		entry = f.entry("api.example.com")
+		require.NotNil(t, entry)
+		assert.Equal(t, "bob", entry.fields[netrcLogin].value.value)
+		assert.Equal(t, "team", entry.fields[netrcAccount].value.value)
+	})
+
+	t.Run("Macro bodies are skipped", func(t *testing.T) {
+		assert.Nil(t, f.entry("fake.com"))
+	})
+
+	t.Run("Default entry", func(t *testing.T) {
+		entry := f.defaultEntry()
+		require.NotNil(t, entry)
+		assert.Equal(t, "anonymous", entry.fields[netrcLogin].value.value)
+	})
+
+	t.Run("Keywords in value position are values", func(t *testing.T) {
+		f, err := parseNetrc("machine a login x password macdef\nmachine b login default account machine\n")
+		require.NoError(t, err)
+		require.Len(t, f.entries, 2)
+		assert.Equal(t, "macdef", f.entry("a").fields[netrcPassword].value.value)
+		assert.Equal(t, "default", f.entry("b").fields[netrcLogin].value.value)
+		assert.Equal(t, "machine", f.entry("b").fields[netrcAccount].value.value)
+		assert.Nil(t, f.defaultEntry())
+	})
+
+	t.Run("Syntax errors", func(t *testing.T) {
+		for _, input := range []string{"machine", "login alice", "machine a password", `machine a password "open`} {
+			_, err := parseNetrc(input)
+			assert.ErrorIs(t, err, ErrNetrcSyntax, input)
+		}
+	})
+}
+
+func TestNetrcFile_Edit(t *testing.T) {
+	edit := func(t *testing.T, fn func(f *netrcFile)) string {
+		f, err := parseNetrc(testNetrc)
+		require.NoError(t, err)
+		fn(f)
+		return f.text
+	}
+
+	t.Run("Replace a value in place", func(t *testing.T) {
+		text := edit(t, func(f *netrcFile) { require.NoError(t, f.set("example.com", netrcPassword, "new")) })
+		assert.Contains(t, text, "machine example.com\n    login alice\n    password new\n\nmachine api.example.com")
+	})
+
+	t.Run("Append a field to an entry", func(t *testing.T) {
+		text := edit(t, func(f *netrcFile) { require.NoError(t, f.set("example.com", netrcAccount, "my account")) })
+		assert.Contains(t, text, "password \"p@ss word \\\"quoted\\\"\" account \"my account\"\n\nmachine api.example.com")
+	})
+
+	t.Run("Add an entry before the default entry", func(t *testing.T) {
+		text := edit(t, func(f *netrcFile) { require.NoError(t, f.set("new.example.com", netrcLogin, "carol")) })
+		assert.Contains(t, text, "machine fake.com login mallory\n\nmachine new.example.com login carol\ndefault login anonymous")
+	})
+
+	t.Run("Add an entry at the end", func(t *testing.T) {
+		f, err := parseNetrc("machine a login x")
+		require.NoError(t, err)
+		require.NoError(t, f.set("b", netrcLogin, ""))
+		assert.Equal(t, "machine a login x\nmachine b login \"\"\n", f.text)
+	})
+
+	t.Run("Keyword values are quoted and read back", func(t *testing.T) {
+		f, err := parseNetrc("machine a login x")
+		require.NoError(t, err)
+		require.NoError(t, f.set("a", netrcPassword, "macdef"))
+		require.NoError(t, f.set("a", netrcAccount, "login"))
+		assert.Equal(t, `machine a login x password "macdef" account "login"`, f.text)
+		assert.Equal(t, "x", f.entry("a").fields[netrcLogin].value.value)
+	})
+
+	t.Run("Edits that break the file are rejected", func(t *testing.T) {
+		f, err := parseNetrc("machine a login x")
+		require.NoError(t, err)
+		assert.ErrorIs(t, f.splice(0, 0, `"`), ErrNetrcSyntax)
+		assert.Equal(t, "machine a login x", f.text)
+	})
+
+	t.Run("Remove a field", func(t *testing.T) {
+		text := edit(t, func(f *netrcFile) {
+			deleted, err := f.delete("example.com", netrcLogin)
+			require.NoError(t, err)
+			assert.True(t, deleted)
+		})
+		assert.Contains(t, text, "machine example.com\n    password")
+	})
+
+	t.Run("Remove an entry with its last field", func(t *testing.T) {
+		f, err := parseNetrc("machine a login x\nmachine b login y\nmachine c login z\n")
+		require.NoError(t, err)
+		deleted, err := f.delete("b", netrcLogin)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		deleted, err = f.delete("b", netrcLogin)
+		require.NoError(t, err)
+		assert.False(t, deleted)
+		assert.Equal(t, "machine a login x\nmachine c login z\n", f.text)
+	})
+
+	t.Run("Remove an entry with its unknown fields", func(t *testing.T) {
+		f, err := parseNetrc("machine a login x\nmachine b login y port 22\nmachine c login z port 23\n")
+		require.NoError(t, err)
+		deleted, err := f.delete("b", netrcLogin)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, "machine a login x\nmachine c login z port 23\n", f.text)
+
+		deleted, err = f.delete("c", netrcLogin)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, "machine a login x\n", f.text)
+	})
+
+	t.Run("Other entries and comments are kept", func(t *testing.T) {
+		text := edit(t, func(f *netrcFile) {
+			require.NoError(t, f.set("api.example.com", netrcPassword, "rotated"))
+			_, err := f.delete("example.com", netrcPassword)
+			require.NoError(t, err)
+		})
+		assert.Equal(t, `# Personal machines
+machine example.com
+    login alice
+
+machine api.example.com login bob password rotated account team port 443
+
+macdef init
+cd /pub
+machine fake.com login mallory
+
+default login anonymous password guest@example.com
+`, text)
+	})
+}
@@ -0,0 +1,180 @@
+package cred
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/r3dpixel/toolkit/filex"
+)
+
+var ErrUnsupportedKey = errors.New("key not supported by the provider")
+
+const (
+	netrcEnv         = "NETRC"
+	netrcPermission  = 0600
+	netrcFileName    = ".netrc"
+	netrcWindowsName = "_netrc"
+)
+
+// NetrcOptions configures the netrc file provider
+type NetrcOptions struct {
+	Path          string // Path of the netrc file (default $NETRC, or ~/.netrc, or ~/_netrc on windows if present)
+	IgnoreDefault bool   // Machines without an entry do not fall back to the default entry
+}
+
+// netrcProvider implements IdentityProvider using a netrc file, the label being the machine name
+type netrcProvider struct {
+	mu        sync.Mutex
+	credLabel string
+	opts      NetrcOptions
+}
+
+// NewNetrcProvider creates a new netrc file based identity provider.
+// The keys username (or login), password and account map to the fields of the machine named by the label;
+// machines without an entry are read from the default entry, which is never written.
+func NewNetrcProvider(credLabel string, opts NetrcOptions) IdentityProvider {
+	// Set default values if needed
+	if opts.Path == "" {
+		opts.Path = defaultNetrcPath()
+	}
+
+	// Create the provider
+	return &netrcProvider{
+		credLabel: credLabel,
+		opts:      opts,
+	}
+}
+
+// Set stores a key-value pair in the machine entry, keeping the other entries and the formatting
+func (p *netrcProvider) Set(key, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Update the field
+	keyword, err := netrcKeyword(key)
+	if err != nil {
+		return err
+	}
+	f, perm, err := p.load()
+	if err != nil {
+		return err
+	}
+	if err := f.set(p.credLabel, keyword, value); err != nil {
+		return err
+	}
+
+	// Write the file back
+	return filex.WriteFileAtomic(p.opts.Path, []byte(f.text), perm)
+}
+
+// Get retrieves a value for the given key from the machine entry (or the default entry)
+func (p *netrcProvider) Get(key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Find the entry
+	keyword, err := netrcKeyword(key)
+	if err != nil {
+		return "", err
+	}
+	f, _, err := p.load()
+	if err != nil {
+		return "", err
+	}
+	entry := f.entry(p.credLabel)
+	if entry == nil && !p.opts.IgnoreDefault {
+		entry = f.defaultEntry()
+	}
+
+	// Return the field if found
+	if entry == nil || !entry.has(keyword) {
+		return "", ErrSecretNotFound
+	}
+	return entry.fields[keyword].value.value, nil
+}
+
+// Delete removes a key from the machine entry, and the entry once empty (deleting a missing key is not an error)
+func (p *netrcProvider) Delete(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Remove the field, skipping the write if it was missing
+	keyword, err := netrcKeyword(key)
+	if err != nil {
+		return err
+	}
+	f, perm, err := p.load()
+	if err != nil {
+		return err
+	}
+	if deleted, err := f.delete(p.credLabel, keyword); err != nil || !deleted {
+		return err
+	}
+
+	// Write the file back
+	return filex.WriteFileAtomic(p.opts.Path, []byte(f.text), perm)
+}
+
+// CredLabel returns the label for the provider
+func (p *netrcProvider) CredLabel() string {
+	return p.credLabel
+}
+
+// load parses the netrc file and returns its permissions (a missing file is empty)
+func (p *netrcProvider) load() (*netrcFile, os.FileMode, error) {
+	// Read the file
+	data, err := os.ReadFile(p.opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &netrcFile{}, netrcPermission, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Keep the permissions of the file
+	perm := os.FileMode(netrcPermission)
+	if info, err := os.Stat(p.opts.Path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	// Parse the file
+	f, err := parseNetrc(string(data))
+	return f, perm, err
+}
+
+// netrcKeyword returns the netrc field of the key
+func netrcKeyword(key string) (string, error) {
+	switch key {
+	case userKey, netrcLogin:
+		return netrcLogin, nil
+	case secretKey:
+		return netrcPassword, nil
+	case netrcAccount:
+		return netrcAccount, nil
+	}
+	return "", fmt.Errorf("%w: %s (netrc supports %s, %s, %s)", ErrUnsupportedKey, key, userKey, secretKey, netrcAccount)
+}
+
+// defaultNetrcPath returns the path of the user's netrc file
+func defaultNetrcPath() string {
+	// Use the path from the environment if set
+	if path := os.Getenv(netrcEnv); path != "" {
+		return path
+	}
+
+	// Use the file in the home directory
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return netrcFileName
+	}
+	if runtime.GOOS == "windows" {
+		if path := filepath.Join(home, netrcWindowsName); filex.FileExists(path) {
+			return path
+		}
+	}
+	return filepath.Join(home, netrcFileName)
+}
@@ -0,0 +1,150 @@
+package cred
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetrcProvider_Lifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".netrc")
+	p := NewNetrcProvider("example.com", NetrcOptions{Path: path})
+
+	t.Run("Get non-existent value fails", func(t *testing.T) {
+		_, err := p.Get(userKey)
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Set and Get successfully", func(t *testing.T) {
+		require.NoError(t, p.Set(userKey, "alice"))
+		require.NoError(t, p.Set(secretKey, "s3cr3t p@ss"))
+		require.NoError(t, p.Set("account", "team"))
+
+		for key, expected := range map[string]string{userKey: "alice", "login": "alice", secretKey: "s3cr3t p@ss", "account": "team"} {
+			value, err := p.Get(key)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "machine example.com login alice password \"s3cr3t p@ss\" account team\n", string(data))
+	})
+
+	t.Run("New files are created with 0600 permissions", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("permissions are not supported on windows")
+		}
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("Unsupported keys fail", func(t *testing.T) {
+		assert.ErrorIs(t, p.Set("token", "value"), ErrUnsupportedKey)
+		_, err := p.Get("token")
+		assert.ErrorIs(t, err, ErrUnsupportedKey)
+	})
+
+	t.Run("Delete and verify", func(t *testing.T) {
+		require.NoError(t, p.Delete(userKey))
+		require.NoError(t, p.Delete(secretKey))
+		require.NoError(t, p.Delete("account"))
+		assert.NoError(t, p.Delete(userKey), "Deleting a non-existent value should not return an error")
+
+		_, err := p.Get(userKey)
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Empty(t, string(data))
+	})
+
+	t.Run("CredLabel", func(t *testing.T) {
+		assert.Equal(t, "example.com", p.CredLabel())
+	})
+}
+
+func TestNetrcProvider_KeywordValues(t *testing.T) {
+	t.Run("Keyword values written by the provider read back", func(t *testing.T) {
+		p := NewNetrcProvider("example.com", NetrcOptions{Path: filepath.Join(t.TempDir(), ".netrc")})
+		require.NoError(t, p.Set(secretKey, "macdef"))
+		require.NoError(t, p.Set(userKey, "alice"))
+
+		for key, expected := range map[string]string{userKey: "alice", secretKey: "macdef"} {
+			value, err := p.Get(key)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+	})
+
+	t.Run("Hand-written keyword values do not hide later machines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".netrc")
+		require.NoError(t, os.WriteFile(path, []byte("machine a login x password macdef\nmachine b login y\n"), 0600))
+
+		value, err := NewNetrcProvider("b", NetrcOptions{Path: path}).Get(userKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "y", value)
+	})
+
+	t.Run("Writes to a file that does not parse fail", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".netrc")
+		require.NoError(t, os.WriteFile(path, []byte(`machine a login "open`), 0600))
+
+		p := NewNetrcProvider("a", NetrcOptions{Path: path})
+		assert.ErrorIs(t, p.Set(userKey, "alice"), ErrNetrcSyntax)
+		assert.ErrorIs(t, p.Delete(userKey), ErrNetrcSyntax)
+	})
+}
+
+func TestNetrcProvider_ExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".netrc")
+	require.NoError(t, os.WriteFile(path, []byte(testNetrc), 0640))
+
+	t.Run("Manager reads the machine identity", func(t *testing.T) {
+		identity, err := NewManager("example.com", Netrc, ManagerOptions{Netrc: NetrcOptions{Path: path}}).Get()
+		assert.NoError(t, err)
+		assert.Equal(t, Identity{User: "alice", Secret: `p@ss word "quoted"`}, identity)
+	})
+
+	t.Run("Unknown machines use the default entry", func(t *testing.T) {
+		identity, err := NewManager("unknown.com", Netrc, ManagerOptions{Netrc: NetrcOptions{Path: path}}).Get()
+		assert.NoError(t, err)
+		assert.Equal(t, Identity{User: "anonymous", Secret: "guest@example.com"}, identity)
+
+		_, err = NewManager("unknown.com", Netrc, ManagerOptions{Netrc: NetrcOptions{Path: path, IgnoreDefault: true}}).Get()
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Machines with an entry do not use the default entry", func(t *testing.T) {
+		_, err := NewNetrcProvider("example.com", NetrcOptions{Path: path}).Get("account")
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("Writes keep the other entries and the permissions", func(t *testing.T) {
+		m := NewManager("api.example.com", Netrc, ManagerOptions{Netrc: NetrcOptions{Path: path}})
+		require.NoError(t, m.SetSecret("rotated"))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Replace(testNetrc, "password secret", "password rotated", 1), string(data))
+
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+		}
+	})
+
+	t.Run("Default path from the environment", func(t *testing.T) {
+		t.Setenv("NETRC", path)
+		user, err := NewNetrcProvider("example.com", NetrcOptions{}).Get(userKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user)
+	})
+}
@@ -0,0 +1,319 @@
+package cred
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+)
+
+var (
+	ErrInvalidRecord = errors.New("invalid credential record type")
+	ErrInvalidField  = errors.New("invalid credential field value")
+)
+
+const recordTag = "cred"
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	bytesType           = reflect.TypeFor[[]byte]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// recordField is a struct field stored under a key
+type recordField struct {
+	index     int
+	key       string
+	optional  bool // Missing keys leave the field unchanged when reading
+	omitEmpty bool // Zero values (including nil pointers) are not written
+}
+
+// Record stores a struct through an IdentityProvider, one key per field, described by `cred` tags:
+//
+//	type Credentials struct {
+//		User    string    `cred:"username"`          // Same keys as IdentityManager
+//		Secret  string    `cred:"password"`
+//		APIKey  string    `cred:"api_key,optional"`  // Missing keys are not an error
+//		Expiry  time.Time `cred:"expiry,optional"`
+//		Refresh *string   `cred:"refresh,omitempty"` // Nil pointers (or zero values) are not written
+//		Cache   string    `cred:"-"`                 // Not stored
+//	}
+//
+// Untagged exported fields use the field name as key. Supported field types are strings, booleans, integers,
+// floats, time.Duration, []byte (base64), encoding.TextMarshaler implementations (e.g. time.Time) and pointers to them.
+// A struct with omitempty pointer fields acts as a partial payload (like IdentityPayload).
+type Record[T any] struct {
+	provider IdentityProvider
+	fields   []recordField
+}
+
+// NewRecord creates a new record storing T through the provider (T must be a struct with supported fields)
+func NewRecord[T any](provider IdentityProvider) (*Record[T], error) {
+	// Validate the type
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidRecord, t)
+	}
+
+	// Collect the stored fields
+	var fields []recordField
+	keys := map[string]string{}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(recordTag)
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		// Parse the tag
+		name, options, _ := strings.Cut(tag, ",")
+		field := recordField{index: i, key: name}
+		if field.key == "" {
+			field.key = sf.Name
+		}
+		for option := range strings.SplitSeq(options, ",") {
+			switch option {
+			case "optional":
+				field.optional = true
+			case "omitempty":
+				field.omitEmpty = true
+			case "":
+			default:
+				return nil, fmt.Errorf("%w: unknown option %q of field %s", ErrInvalidRecord, option, sf.Name)
+			}
+		}
+
+		// Reject unsupported types and duplicate keys
+		if !supportedField(sf.Type) {
+			return nil, fmt.Errorf("%w: unsupported type %s of field %s", ErrInvalidRecord, sf.Type, sf.Name)
+		}
+		if other, ok := keys[field.key]; ok {
+			return nil, fmt.Errorf("%w: fields %s and %s use the key %q", ErrInvalidRecord, other, sf.Name, field.key)
+		}
+		keys[field.key] = sf.Name
+		fields = append(fields, field)
+	}
+
+	// Create the record
+	return &Record[T]{provider: provider, fields: fields}, nil
+}
+
+// Get reads every field, failing if a required key is missing (the error satisfies IsNotFound)
+func (r *Record[T]) Get() (T, error) {
+	var value T
+	v := reflect.ValueOf(&value).Elem()
+	for _, field := range r.fields {
+		// Read the key, skipping missing optional keys
+		s, err := r.provider.Get(field.key)
+		if err != nil {
+			if field.optional && IsNotFound(err) {
+				continue
+			}
+			return value, err
+		}
+
+		// Decode the field
+		if err := decodeField(s, v.Field(field.index)); err != nil {
+			return value, trace.Error().
+				Field(trace.LABEL, r.provider.CredLabel()).
+				Field(trace.KEY, field.key).
+				Wrap(fmt.Errorf("%w: %w", ErrInvalidField, err))
+		}
+	}
+	return value, nil
+}
+
+// Set writes every field (omitempty fields only when not zero)
+func (r *Record[T]) Set(value T) error {
+	v := reflect.ValueOf(&value).Elem()
+	for _, field := range r.fields {
+		if err := r.set(field, v.Field(field.index), field.omitEmpty); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFields writes only the fields stored under the given keys (even when zero)
+func (r *Record[T]) SetFields(value T, keys ...string) error {
+	// Validate the keys before writing anything
+	selected := make([]recordField, 0, len(keys))
+	for _, key := range keys {
+		i := r.fieldIndex(key)
+		if i < 0 {
+			return fmt.Errorf("%w: unknown key %q", ErrInvalidRecord, key)
+		}
+		selected = append(selected, r.fields[i])
+	}
+
+	// Write the fields (nil pointers are skipped)
+	v := reflect.ValueOf(&value).Elem()
+	for _, field := range selected {
+		if err := r.set(field, v.Field(field.index), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes every field
+func (r *Record[T]) Delete() error {
+	for _, field := range r.fields {
+		if err := r.provider.Delete(field.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys of the stored fields
+func (r *Record[T]) Keys() []string {
+	keys := make([]string, len(r.fields))
+	for i, field := range r.fields {
+		keys[i] = field.key
+	}
+	return keys
+}
+
+// CredLabel returns the label of the provider
+func (r *Record[T]) CredLabel() string {
+	return r.provider.CredLabel()
+}
+
+// set encodes and writes a single field
+func (r *Record[T]) set(field recordField, v reflect.Value, omitEmpty bool) error {
+	// Skip nil pointers, and zero values if requested
+	if (v.Kind() == reflect.Pointer && v.IsNil()) || (omitEmpty && v.IsZero()) {
+		return nil
+	}
+
+	// Encode and write the field
+	s, err := encodeField(v)
+	if err != nil {
+		return trace.Error().
+			Field(trace.LABEL, r.provider.CredLabel()).
+			Field(trace.KEY, field.key).
+			Wrap(fmt.Errorf("%w: %w", ErrInvalidField, err))
+	}
+	return r.provider.Set(field.key, s)
+}
+
+// fieldIndex returns the index of the field stored under the key (-1 if none)
+func (r *Record[T]) fieldIndex(key string) int {
+	for i, field := range r.fields {
+		if field.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// supportedField returns true if the type can be encoded as a string
+func supportedField(t reflect.Type) bool {
+	// Dereference a single pointer
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Check the special types, then the kinds
+	if t == durationType || t == bytesType ||
+		(t.Implements(textMarshalerType) && reflect.PointerTo(t).Implements(textUnmarshalerType)) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// encodeField encodes the (non-nil) field value as a string
+func encodeField(v reflect.Value) (string, error) {
+	// Dereference pointers
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	// Encode the special types, then the kinds
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type() == bytesType:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return v.String(), nil
+	}
+}
+
+// decodeField decodes the string into the field value (allocating pointers)
+func decodeField(s string, v reflect.Value) error {
+	// Allocate pointers
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := decodeField(s, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	// Decode the special types
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return err
+	case v.Type() == bytesType:
+		b, err := base64.StdEncoding.DecodeString(s)
+		v.SetBytes(b)
+		return err
+	case reflect.PointerTo(v.Type()).Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	// Decode the kinds
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		v.SetBool(b)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(i)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(u)
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(f)
+		return err
+	default:
+		v.SetString(s)
+		return nil
+	}
+}
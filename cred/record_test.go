@@ -0,0 +1,214 @@
+package cred
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCredentials struct {
+	User      string        `cred:"username"`
+	Secret    string        `cred:"password"`
+	APIKey    string        `cred:"api_key,optional"`
+	ClientID  int64         `cred:"client_id,optional"`
+	Expiry    time.Time     `cred:"expiry,optional,omitempty"`
+	Lifetime  time.Duration `cred:"lifetime,optional,omitempty"`
+	Seed      []byte        `cred:"seed,optional,omitempty"`
+	Refresh   *string       `cred:"refresh,optional,omitempty"`
+	Verified  bool          `cred:"verified,optional"`
+	Ratio     float32       `cred:"ratio,optional"`
+	Address   netip.Addr    `cred:"address,optional,omitempty"`
+	Region    string        `cred:",optional"`
+	Cache     string        `cred:"-"`
+	unexposed string
+}
+
+type testPayload struct {
+	User    *string `cred:"username,omitempty"`
+	Secret  *string `cred:"password,omitempty"`
+	Refresh *string `cred:"refresh,omitempty"`
+}
+
+func TestNewRecord(t *testing.T) {
+	t.Run("Keys of the stored fields", func(t *testing.T) {
+		r, err := NewRecord[testCredentials](NewMemoryProvider("label"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"username", "password", "api_key", "client_id", "expiry", "lifetime",
+			"seed", "refresh", "verified", "ratio", "address", "Region",
+		}, r.Keys())
+		assert.Equal(t, "label", r.CredLabel())
+	})
+
+	t.Run("Invalid types are rejected", func(t *testing.T) {
+		_, err := NewRecord[string](NewMemoryProvider("label"))
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+
+		_, err = NewRecord[struct {
+			Values []string
+		}](NewMemoryProvider("label"))
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+
+		_, err = NewRecord[struct {
+			A string `cred:"key"`
+			B string `cred:"key"`
+		}](NewMemoryProvider("label"))
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+
+		_, err = NewRecord[struct {
+			A string `cred:"key,unknown"`
+		}](NewMemoryProvider("label"))
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+	})
+}
+
+func TestRecord_Lifecycle(t *testing.T) {
+	p := NewMemoryProvider("label")
+	r, err := NewRecord[testCredentials](p)
+	require.NoError(t, err)
+
+	refresh := "refresh-token"
+	value := testCredentials{
+		User:     "user",
+		Secret:   "secret",
+		APIKey:   "api-key",
+		ClientID: -42,
+		Expiry:   time.Date(2030, 1, 2, 3, 4, 5, 6, time.UTC),
+		Lifetime: 90 * time.Minute,
+		Seed:     []byte{0x00, 0xFF, 0x10},
+		Refresh:  &refresh,
+		Verified: true,
+		Ratio:    0.25,
+		Address:  netip.MustParseAddr("10.0.0.1"),
+		Region:   "eu",
+		Cache:    "not stored",
+	}
+
+	t.Run("Get non-existent record fails", func(t *testing.T) {
+		_, err := r.Get()
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("Set and Get successfully", func(t *testing.T) {
+		require.NoError(t, r.Set(value))
+		assert.Equal(t, "2030-01-02T03:04:05.000000006Z", p.Values()["expiry"])
+		assert.Equal(t, "1h30m0s", p.Values()["lifetime"])
+		assert.Equal(t, "AP8Q", p.Values()["seed"])
+
+		retrieved, err := r.Get()
+		require.NoError(t, err)
+		expected := value
+		expected.Cache = ""
+		assert.Equal(t, expected, retrieved)
+	})
+
+	t.Run("Delete removes every field", func(t *testing.T) {
+		require.NoError(t, r.Delete())
+		assert.Empty(t, p.Values())
+	})
+}
+
+func TestRecord_Compatibility(t *testing.T) {
+	p := NewMemoryProvider("label")
+	m := NewManagerWithProvider(p)
+	r, err := NewRecord[testCredentials](p)
+	require.NoError(t, err)
+
+	t.Run("Reads identities written by the manager", func(t *testing.T) {
+		require.NoError(t, m.SetAll(Identity{User: "user", Secret: "secret"}))
+
+		retrieved, err := r.Get()
+		require.NoError(t, err)
+		assert.Equal(t, testCredentials{User: "user", Secret: "secret"}, retrieved)
+	})
+
+	t.Run("Manager reads identities written by the record", func(t *testing.T) {
+		require.NoError(t, r.Set(testCredentials{User: "other-user", Secret: "other-secret", APIKey: "key"}))
+
+		identity, err := m.Get()
+		require.NoError(t, err)
+		assert.Equal(t, Identity{User: "other-user", Secret: "other-secret"}, identity)
+	})
+
+	t.Run("Missing required field fails", func(t *testing.T) {
+		require.NoError(t, p.Delete("password"))
+
+		_, err := r.Get()
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+}
+
+func TestRecord_PartialUpdates(t *testing.T) {
+	p := NewMemoryProvider("label")
+	full, err := NewRecord[testCredentials](p)
+	require.NoError(t, err)
+	require.NoError(t, full.Set(testCredentials{User: "user", Secret: "secret", APIKey: "key"}))
+
+	t.Run("Payload writes only non-nil fields", func(t *testing.T) {
+		payload, err := NewRecord[testPayload](p)
+		require.NoError(t, err)
+
+		refresh := "new-refresh"
+		require.NoError(t, payload.Set(testPayload{Refresh: &refresh}))
+
+		retrieved, err := full.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "user", retrieved.User)
+		assert.Equal(t, "secret", retrieved.Secret)
+		require.NotNil(t, retrieved.Refresh)
+		assert.Equal(t, "new-refresh", *retrieved.Refresh)
+	})
+
+	t.Run("SetFields writes only the given keys", func(t *testing.T) {
+		require.NoError(t, full.SetFields(testCredentials{Secret: "rotated", APIKey: ""}, "password", "api_key"))
+
+		retrieved, err := full.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "user", retrieved.User)
+		assert.Equal(t, "rotated", retrieved.Secret)
+		assert.Equal(t, "", retrieved.APIKey)
+	})
+
+	t.Run("SetFields rejects unknown keys before writing", func(t *testing.T) {
+		err := full.SetFields(testCredentials{Secret: "not-written"}, "password", "unknown")
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+
+		secret, err := p.Get("password")
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", secret)
+	})
+}
+
+func TestRecord_Errors(t *testing.T) {
+	t.Run("Invalid stored value", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		r, err := NewRecord[testCredentials](p)
+		require.NoError(t, err)
+		require.NoError(t, r.Set(testCredentials{User: "user", Secret: "secret"}))
+		require.NoError(t, p.Set("client_id", "not a number"))
+
+		_, err = r.Get()
+		assert.ErrorIs(t, err, ErrInvalidField)
+		var tracedErr *trace.Err
+		require.ErrorAs(t, err, &tracedErr)
+		assert.Equal(t, "label", tracedErr.GetField(trace.LABEL))
+		assert.Equal(t, "client_id", tracedErr.GetField(trace.KEY))
+	})
+
+	t.Run("Backend failure of an optional field", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		r, err := NewRecord[testCredentials](p)
+		require.NoError(t, err)
+		require.NoError(t, r.Set(testCredentials{User: "user", Secret: "secret"}))
+
+		errUnavailable := errors.New("backend unavailable")
+		p.Fail(OpGet, "api_key", errUnavailable)
+		_, err = r.Get()
+		assert.ErrorIs(t, err, errUnavailable)
+	})
+}
@@ -0,0 +1,142 @@
+package cred
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Redacted replaces the value of a Secret when it is printed, encoded or logged
+const Redacted = "[REDACTED]"
+
+// Secret holds a sensitive value that redacts itself when printed (fmt), encoded (JSON) or logged (zerolog).
+// The plaintext is only exposed through Reveal, and its buffer is zeroed by Destroy (or once garbage collected).
+// A nil Secret is empty.
+type Secret struct {
+	mu  sync.RWMutex
+	buf []byte
+}
+
+// NewSecret creates a new secret holding a copy of the value
+// (the string itself cannot be zeroed, prefer NewSecretBytes when the value comes from a buffer)
+func NewSecret(value string) *Secret {
+	return newSecret([]byte(value))
+}
+
+// NewSecretBytes creates a new secret holding a copy of the value (the caller may clear its slice afterward)
+func NewSecretBytes(value []byte) *Secret {
+	return newSecret(append([]byte(nil), value...))
+}
+
+// newSecret creates a new secret owning the buffer, zeroed once the secret is garbage collected
+func newSecret(buf []byte) *Secret {
+	s := &Secret{buf: buf}
+	runtime.AddCleanup(s, func(buf []byte) { clear(buf) }, buf)
+	return s
+}
+
+// Reveal returns the plaintext (empty once destroyed)
+func (s *Secret) Reveal() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return string(s.buf)
+}
+
+// Len returns the length of the plaintext (0 once destroyed)
+func (s *Secret) Len() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.buf)
+}
+
+// Equal compares the plaintexts in constant time
+func (s *Secret) Equal(other *Secret) bool {
+	if s == other {
+		return true
+	}
+	if s == nil || other == nil {
+		return s.Len() == other.Len()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	return subtle.ConstantTimeCompare(s.buf, other.buf) == 1
+}
+
+// Destroy zeroes the buffer of the secret, which becomes empty
+func (s *Secret) Destroy() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.buf)
+	s.buf = nil
+}
+
+// String returns Redacted
+func (s *Secret) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted
+func (s *Secret) GoString() string {
+	return Redacted
+}
+
+// Format writes Redacted for every verb (quoted for %q)
+func (s *Secret) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = io.WriteString(f, strconv.Quote(Redacted))
+		return
+	}
+	_, _ = io.WriteString(f, Redacted)
+}
+
+// MarshalJSON encodes Redacted as a JSON string
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(Redacted)), nil
+}
+
+// MarshalText encodes Redacted (e.g. as a map key or by text encoders)
+func (s *Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// MarshalZerologObject logs the secret as a redacted object (zerolog.Event.Object)
+func (s *Secret) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("secret", Redacted)
+}
+
+// SecureIdentity stores a pair of user - secret, the secret being redacted
+type SecureIdentity struct {
+	User   string
+	Secret *Secret
+}
+
+// Secure converts the identity, copying its secret into a Secret
+func (i Identity) Secure() SecureIdentity {
+	return SecureIdentity{User: i.User, Secret: NewSecret(i.Secret)}
+}
+
+// Reveal converts the identity back to a plain Identity (e.g. for a reqx.RefreshTokenFunc)
+func (i SecureIdentity) Reveal() Identity {
+	return Identity{User: i.User, Secret: i.Secret.Reveal()}
+}
+
+// Destroy zeroes the secret of the identity
+func (i SecureIdentity) Destroy() {
+	i.Secret.Destroy()
+}
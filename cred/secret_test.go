@@ -0,0 +1,131 @@
+package cred
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/r3dpixel/toolkit/sonicx"
+	"github.com/r3dpixel/toolkit/trace"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_Lifecycle(t *testing.T) {
+	t.Run("Reveal returns the plaintext", func(t *testing.T) {
+		s := NewSecret("hunter2")
+		assert.Equal(t, "hunter2", s.Reveal())
+		assert.Equal(t, 7, s.Len())
+	})
+
+	t.Run("Bytes are copied", func(t *testing.T) {
+		value := []byte("hunter2")
+		s := NewSecretBytes(value)
+		clear(value)
+		assert.Equal(t, "hunter2", s.Reveal())
+	})
+
+	t.Run("Destroy zeroes the buffer", func(t *testing.T) {
+		s := NewSecret("hunter2")
+		buf := s.buf
+		s.Destroy()
+		assert.Equal(t, make([]byte, 7), buf)
+		assert.Empty(t, s.Reveal())
+		assert.Zero(t, s.Len())
+		s.Destroy()
+	})
+
+	t.Run("Nil secret is empty", func(t *testing.T) {
+		var s *Secret
+		assert.Empty(t, s.Reveal())
+		assert.Zero(t, s.Len())
+		assert.Equal(t, Redacted, fmt.Sprint(s))
+		s.Destroy()
+	})
+
+	t.Run("Equal compares the plaintexts", func(t *testing.T) {
+		assert.True(t, NewSecret("a").Equal(NewSecret("a")))
+		assert.False(t, NewSecret("a").Equal(NewSecret("b")))
+		assert.True(t, NewSecret("").Equal(nil))
+		assert.False(t, NewSecret("a").Equal(nil))
+	})
+}
+
+func TestSecret_Redaction(t *testing.T) {
+	s := NewSecret("hunter2")
+	identity := Identity{User: "john", Secret: "hunter2"}.Secure()
+
+	t.Run("fmt", func(t *testing.T) {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+			out := fmt.Sprintf(format, s)
+			assert.NotContains(t, out, "hunter2", format)
+			assert.Contains(t, out, Redacted, format)
+		}
+		assert.Equal(t, Redacted, s.String())
+		assert.Equal(t, Redacted, s.GoString())
+		assert.NotContains(t, fmt.Sprintf("%+v", identity), "hunter2")
+		assert.NotContains(t, fmt.Sprintf("%#v", identity), "hunter2")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := sonicx.Config.Marshal(identity)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"User":"john","Secret":"[REDACTED]"}`, string(data))
+	})
+
+	t.Run("zerolog", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf)
+		logger.Info().Interface("identity", identity).Object("secret", s).Stringer("stringer", s).Msg("")
+		assert.NotContains(t, buf.String(), "hunter2")
+		assert.Contains(t, buf.String(), Redacted)
+	})
+
+	t.Run("trace fields", func(t *testing.T) {
+		err := trace.Error().Field(trace.KEY, s).Wrap(errors.New("failed"))
+		fields := trace.ErrorMarshalFunc(err)
+		data, marshalErr := sonicx.Config.Marshal(fields)
+		require.NoError(t, marshalErr)
+		assert.NotContains(t, string(data), "hunter2")
+		assert.Contains(t, string(data), Redacted)
+	})
+}
+
+func TestSecureIdentity(t *testing.T) {
+	t.Run("Reveal converts back", func(t *testing.T) {
+		identity := Identity{User: "john", Secret: "hunter2"}
+		secure := identity.Secure()
+		assert.Equal(t, identity, secure.Reveal())
+		secure.Destroy()
+		assert.Equal(t, Identity{User: "john"}, secure.Reveal())
+	})
+
+	t.Run("Manager variants", func(t *testing.T) {
+		m := NewSecureManager("label", Memory)
+
+		_, err := m.GetSecure()
+		assert.True(t, IsNotFound(err))
+
+		require.NoError(t, m.SetAllSecure(SecureIdentity{User: "john", Secret: NewSecret("hunter2")}))
+		identity, err := m.GetSecure()
+		require.NoError(t, err)
+		assert.Equal(t, "john", identity.User)
+		assert.Equal(t, "hunter2", identity.Secret.Reveal())
+
+		require.NoError(t, m.SetSecretSecure(NewSecret("changed")))
+		secret, err := m.GetSecretSecure()
+		require.NoError(t, err)
+		assert.True(t, secret.Equal(NewSecret("changed")))
+		plain, err := m.GetSecret()
+		require.NoError(t, err)
+		assert.Equal(t, "changed", plain)
+	})
+
+	t.Run("Manager with provider", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		require.NoError(t, NewSecureManagerWithProvider(p).SetSecretSecure(NewSecret("hunter2")))
+		assert.Equal(t, map[string]string{secretKey: "hunter2"}, p.Values())
+	})
+}
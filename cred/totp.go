@@ -0,0 +1,287 @@
+package cred
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTOTP = errors.New("invalid TOTP")
+
+// TOTPKey is the key the TOTP seed is stored under, next to the identity
+const TOTPKey = "totp"
+
+const (
+	totpScheme        = "otpauth"
+	totpType          = "totp"
+	defaultTOTPDigits = 6
+	defaultTOTPPeriod = 30 * time.Second
+	maxTOTPDigits     = 10
+)
+
+// TOTPAlgorithm the HMAC hash of a TOTP
+type TOTPAlgorithm byte
+
+const (
+	SHA1   TOTPAlgorithm = iota // HMAC-SHA1 (default, used by most authenticators)
+	SHA256                      // HMAC-SHA256
+	SHA512                      // HMAC-SHA512
+)
+
+// totpAlgorithms maps from algorithm to name and hash
+var totpAlgorithms = [...]struct {
+	name string
+	hash func() hash.Hash
+}{
+	SHA1:   {"SHA1", sha1.New},
+	SHA256: {"SHA256", sha256.New},
+	SHA512: {"SHA512", sha512.New},
+}
+
+// String returns the name of the algorithm (as used in otpauth URIs)
+func (a TOTPAlgorithm) String() string {
+	if int(a) < len(totpAlgorithms) {
+		return totpAlgorithms[a].name
+	}
+	return "TOTPAlgorithm(" + strconv.Itoa(int(a)) + ")"
+}
+
+// TOTP generates RFC 6238 time-based one-time passwords from a seed.
+// It is stored as an otpauth URI (see MarshalText), so it can be a Record field (Skew as a non-standard skew parameter).
+type TOTP struct {
+	Secret    []byte        // Shared seed (decoded from base32)
+	Algorithm TOTPAlgorithm // HMAC hash (default SHA1)
+	Digits    int           // Number of digits of the codes (default 6)
+	Period    time.Duration // Validity of each code (default 30s)
+	Skew      int           // Number of periods accepted before and after the current one by Validate
+	Issuer    string        // Provider of the account (informative)
+	Account   string        // Name of the account (informative)
+}
+
+// ParseTOTP parses an otpauth://totp/ URI, or a bare base32 seed (with the default parameters)
+func ParseTOTP(s string) (TOTP, error) {
+	// Parse a bare seed
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToLower(s), totpScheme+":") {
+		secret, err := decodeTOTPSecret(s)
+		return TOTP{Secret: secret}, err
+	}
+
+	// Parse the URI
+	u, err := url.Parse(s)
+	if err != nil {
+		return TOTP{}, fmt.Errorf("%w: %w", ErrInvalidTOTP, err)
+	}
+	if !strings.EqualFold(u.Host, totpType) {
+		return TOTP{}, fmt.Errorf("%w: unsupported type %q", ErrInvalidTOTP, u.Host)
+	}
+
+	// Parse the label (issuer:account) and the parameters
+	totp := TOTP{}
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		totp.Issuer, totp.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		totp.Account = label
+	}
+	query := u.Query()
+	if issuer := query.Get("issuer"); issuer != "" {
+		totp.Issuer = issuer
+	}
+	if totp.Secret, err = decodeTOTPSecret(query.Get("secret")); err != nil {
+		return TOTP{}, err
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		found := false
+		for a, entry := range totpAlgorithms {
+			if strings.EqualFold(algorithm, entry.name) {
+				totp.Algorithm, found = TOTPAlgorithm(a), true
+			}
+		}
+		if !found {
+			return TOTP{}, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidTOTP, algorithm)
+		}
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if totp.Digits, err = strconv.Atoi(digits); err != nil || totp.Digits < 1 || totp.Digits > maxTOTPDigits {
+			return TOTP{}, fmt.Errorf("%w: invalid digits %q", ErrInvalidTOTP, digits)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		seconds, err := strconv.Atoi(period)
+		if err != nil || seconds <= 0 {
+			return TOTP{}, fmt.Errorf("%w: invalid period %q", ErrInvalidTOTP, period)
+		}
+		totp.Period = time.Duration(seconds) * time.Second
+	}
+	if skew := query.Get("skew"); skew != "" {
+		if totp.Skew, err = strconv.Atoi(skew); err != nil || totp.Skew < 0 {
+			return TOTP{}, fmt.Errorf("%w: invalid skew %q", ErrInvalidTOTP, skew)
+		}
+	}
+	return totp, nil
+}
+
+// CodeAt returns the code valid at the given time
+func (t TOTP) CodeAt(at time.Time) string {
+	return t.code(t.counter(at))
+}
+
+// Code returns the current code and how long it remains valid
+// (callers logging in unattended may wait for the next code if it is about to expire)
+func (t TOTP) Code() (string, time.Duration) {
+	now := time.Now()
+	period := t.period()
+	remaining := period - time.Duration(now.UnixNano()%int64(period))
+	return t.CodeAt(now), remaining
+}
+
+// Validate returns true if the code is valid at the given time, within the Skew window
+func (t TOTP) Validate(code string, at time.Time) bool {
+	counter, skew := t.counter(at), uint64(max(t.Skew, 0))
+	valid := 0
+	for c := counter - min(counter, skew); c <= counter+skew; c++ {
+		valid |= subtle.ConstantTimeCompare([]byte(code), []byte(t.code(c)))
+	}
+	return valid == 1
+}
+
+// URI returns the otpauth://totp/ URI of the TOTP (the format of authenticator QR codes, which ignore the skew)
+func (t TOTP) URI() string {
+	// Build the label
+	label := t.Account
+	if t.Issuer != "" {
+		label = t.Issuer + ":" + t.Account
+	}
+
+	// Build the parameters
+	query := url.Values{}
+	query.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(t.Secret))
+	if t.Issuer != "" {
+		query.Set("issuer", t.Issuer)
+	}
+	query.Set("algorithm", t.Algorithm.String())
+	query.Set("digits", strconv.Itoa(t.digits()))
+	query.Set("period", strconv.Itoa(int(t.period()/time.Second)))
+	if t.Skew > 0 {
+		query.Set("skew", strconv.Itoa(t.Skew))
+	}
+
+	// Build the URI
+	u := url.URL{Scheme: totpScheme, Host: totpType, Path: "/" + label, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// MarshalText returns the otpauth URI of the TOTP
+func (t TOTP) MarshalText() ([]byte, error) {
+	return []byte(t.URI()), nil
+}
+
+// UnmarshalText parses an otpauth URI or a bare base32 seed
+func (t *TOTP) UnmarshalText(text []byte) error {
+	parsed, err := ParseTOTP(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// counter returns the number of periods since the Unix epoch at the given time
+func (t TOTP) counter(at time.Time) uint64 {
+	return uint64(max(at.Unix(), 0)) / uint64(t.period()/time.Second)
+}
+
+// code returns the HOTP code of the counter (RFC 4226)
+func (t TOTP) code(counter uint64) string {
+	// Compute the HMAC of the counter
+	newHash := sha1.New
+	if int(t.Algorithm) < len(totpAlgorithms) {
+		newHash = totpAlgorithms[t.Algorithm].hash
+	}
+	mac := hmac.New(newHash, t.Secret)
+	mac.Write(binary.BigEndian.AppendUint64(nil, counter))
+	sum := mac.Sum(nil)
+
+	// Truncate dynamically to 31 bits
+	offset := sum[len(sum)-1] & 0x0F
+	value := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7FFFFFFF)
+
+	// Keep the requested digits, padded with zeros
+	digits := t.digits()
+	modulo := uint64(1)
+	for range digits {
+		modulo *= 10
+	}
+	code := strconv.FormatUint(value%modulo, 10)
+	return strings.Repeat("0", digits-len(code)) + code
+}
+
+// digits returns the number of digits, or the default
+func (t TOTP) digits() int {
+	if t.Digits <= 0 || t.Digits > maxTOTPDigits {
+		return defaultTOTPDigits
+	}
+	return t.Digits
+}
+
+// period returns the period (at least one second), or the default
+func (t TOTP) period() time.Duration {
+	if t.Period < time.Second {
+		return defaultTOTPPeriod
+	}
+	return t.Period.Truncate(time.Second)
+}
+
+// decodeTOTPSecret decodes a base32 seed (case-insensitive, spaces, hyphens and padding are ignored)
+func decodeTOTPSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	if s == "" {
+		return nil, fmt.Errorf("%w: missing secret", ErrInvalidTOTP)
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTOTP, err)
+	}
+	return secret, nil
+}
+
+// SetTOTP stores the TOTP in the provider, next to the identity
+func SetTOTP(provider IdentityProvider, totp TOTP) error {
+	return provider.Set(TOTPKey, totp.URI())
+}
+
+// GetTOTP retrieves the TOTP stored in the provider
+func GetTOTP(provider IdentityProvider) (TOTP, error) {
+	uri, err := provider.Get(TOTPKey)
+	if err != nil {
+		return TOTP{}, err
+	}
+	return ParseTOTP(uri)
+}
+
+// DeleteTOTP removes the TOTP stored in the provider
+func DeleteTOTP(provider IdentityProvider) error {
+	return provider.Delete(TOTPKey)
+}
+
+// TOTPCode returns the current code of the TOTP stored in the provider
+func TOTPCode(provider IdentityProvider) (string, error) {
+	totp, err := GetTOTP(provider)
+	if err != nil {
+		return "", err
+	}
+	code, _ := totp.Code()
+	return code, nil
+}
@@ -0,0 +1,215 @@
+package cred
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP_RFC6238(t *testing.T) {
+	// Test vectors of RFC 6238 (appendix B)
+	seeds := map[TOTPAlgorithm]string{
+		SHA1:   "12345678901234567890",
+		SHA256: "12345678901234567890123456789012",
+		SHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		unix  int64
+		codes map[TOTPAlgorithm]string
+	}{
+		{59, map[TOTPAlgorithm]string{SHA1: "94287082", SHA256: "46119246", SHA512: "90693936"}},
+		{1111111109, map[TOTPAlgorithm]string{SHA1: "07081804", SHA256: "68084774", SHA512: "25091201"}},
+		{1111111111, map[TOTPAlgorithm]string{SHA1: "14050471", SHA256: "67062674", SHA512: "99943326"}},
+		{1234567890, map[TOTPAlgorithm]string{SHA1: "89005924", SHA256: "91819424", SHA512: "93441116"}},
+		{2000000000, map[TOTPAlgorithm]string{SHA1: "69279037", SHA256: "90698825", SHA512: "38618901"}},
+		{20000000000, map[TOTPAlgorithm]string{SHA1: "65353130", SHA256: "77737706", SHA512: "47863826"}},
+	}
+
+	for _, tt := range tests {
+		for algorithm, code := range tt.codes {
+			t.Run(algorithm.String()+"/"+time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+				totp := TOTP{Secret: []byte(seeds[algorithm]), Algorithm: algorithm, Digits: 8}
+				assert.Equal(t, code, totp.CodeAt(time.Unix(tt.unix, 0)))
+				assert.True(t, totp.Validate(code, time.Unix(tt.unix, 0)))
+			})
+		}
+	}
+}
+
+func TestTOTP_Defaults(t *testing.T) {
+	t.Run("Six digits over 30 seconds", func(t *testing.T) {
+		totp := TOTP{Secret: []byte("12345678901234567890")}
+		assert.Equal(t, "287082", totp.CodeAt(time.Unix(59, 0)))
+		assert.Equal(t, totp.CodeAt(time.Unix(30, 0)), totp.CodeAt(time.Unix(59, 0)))
+		assert.NotEqual(t, totp.CodeAt(time.Unix(59, 0)), totp.CodeAt(time.Unix(60, 0)))
+	})
+
+	t.Run("Current code and remaining validity", func(t *testing.T) {
+		totp := TOTP{Secret: []byte("12345678901234567890"), Period: 10 * time.Second}
+		code, remaining := totp.Code()
+		assert.Len(t, code, 6)
+		assert.Greater(t, remaining, time.Duration(0))
+		assert.LessOrEqual(t, remaining, 10*time.Second)
+	})
+
+	t.Run("Algorithm names", func(t *testing.T) {
+		assert.Equal(t, "SHA1", SHA1.String())
+		assert.Equal(t, "SHA512", SHA512.String())
+		assert.Equal(t, "TOTPAlgorithm(9)", TOTPAlgorithm(9).String())
+	})
+}
+
+func TestTOTP_Validate(t *testing.T) {
+	totp := TOTP{Secret: []byte("12345678901234567890")}
+	at := time.Unix(1111111111, 0)
+	previous, current, next := totp.CodeAt(at.Add(-30*time.Second)), totp.CodeAt(at), totp.CodeAt(at.Add(30*time.Second))
+
+	t.Run("Without skew only the current code is valid", func(t *testing.T) {
+		assert.True(t, totp.Validate(current, at))
+		assert.False(t, totp.Validate(previous, at))
+		assert.False(t, totp.Validate(next, at))
+		assert.False(t, totp.Validate("", at))
+	})
+
+	t.Run("Skew accepts adjacent periods", func(t *testing.T) {
+		skewed := totp
+		skewed.Skew = 1
+		assert.True(t, skewed.Validate(previous, at))
+		assert.True(t, skewed.Validate(next, at))
+		assert.False(t, skewed.Validate(totp.CodeAt(at.Add(time.Minute)), at))
+	})
+
+	t.Run("Skew at the epoch", func(t *testing.T) {
+		skewed := totp
+		skewed.Skew = 2
+		assert.True(t, skewed.Validate(totp.CodeAt(time.Unix(0, 0)), time.Unix(0, 0)))
+	})
+}
+
+func TestParseTOTP(t *testing.T) {
+	t.Run("Full URI", func(t *testing.T) {
+		totp, err := ParseTOTP("otpauth://totp/ACME%20Co:john@example.com?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" +
+			"&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60")
+		require.NoError(t, err)
+		assert.Equal(t, TOTP{
+			Secret:    []byte("12345678901234567890"),
+			Algorithm: SHA256,
+			Digits:    8,
+			Period:    time.Minute,
+			Issuer:    "ACME Co",
+			Account:   "john@example.com",
+		}, totp)
+	})
+
+	t.Run("Minimal URI uses the defaults", func(t *testing.T) {
+		totp, err := ParseTOTP("otpauth://totp/john?secret=gezdgnbvgy3tqojqgezdgnbvgy3tqojq")
+		require.NoError(t, err)
+		assert.Equal(t, TOTP{Secret: []byte("12345678901234567890"), Account: "john"}, totp)
+		assert.Equal(t, "287082", totp.CodeAt(time.Unix(59, 0)))
+	})
+
+	t.Run("Bare seed with spaces and padding", func(t *testing.T) {
+		totp, err := ParseTOTP(" gezd gnbv gy3t qojq gezd gnbv gy3t qojq== ")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("12345678901234567890"), totp.Secret)
+	})
+
+	t.Run("Invalid inputs", func(t *testing.T) {
+		for _, s := range []string{
+			"",
+			"not base32!",
+			"otpauth://hotp/john?secret=GEZDGNBV&counter=1",
+			"otpauth://totp/john",
+			"otpauth://totp/john?secret=GEZDGNBV&algorithm=MD5",
+			"otpauth://totp/john?secret=GEZDGNBV&digits=0",
+			"otpauth://totp/john?secret=GEZDGNBV&digits=eleven",
+			"otpauth://totp/john?secret=GEZDGNBV&period=-30",
+			"otpauth://totp/john?secret=GEZDGNBV&skew=-1",
+			"otpauth://totp/john?secret=GEZDGNBV&skew=one",
+		} {
+			_, err := ParseTOTP(s)
+			assert.ErrorIs(t, err, ErrInvalidTOTP, s)
+		}
+	})
+
+	t.Run("URI round trip", func(t *testing.T) {
+		totp := TOTP{Secret: []byte("seed"), Algorithm: SHA512, Digits: 7, Period: 45 * time.Second, Skew: 2, Issuer: "ACME", Account: "john"}
+		uri := totp.URI()
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/ACME:john?"))
+		assert.Contains(t, uri, "skew=2")
+		parsed, err := ParseTOTP(uri)
+		require.NoError(t, err)
+		assert.Equal(t, totp, parsed)
+		assert.NotContains(t, TOTP{Secret: []byte("seed")}.URI(), "skew")
+	})
+}
+
+func TestTOTP_Storage(t *testing.T) {
+	totp := TOTP{Secret: []byte("12345678901234567890"), Issuer: "ACME", Account: "john"}
+
+	t.Run("Provider functions", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		_, err := TOTPCode(p)
+		assert.True(t, IsNotFound(err))
+
+		require.NoError(t, SetTOTP(p, totp))
+		stored, err := GetTOTP(p)
+		require.NoError(t, err)
+		assert.Equal(t, totp.Secret, stored.Secret)
+
+		skewed := totp
+		skewed.Skew = 1
+		require.NoError(t, SetTOTP(p, skewed))
+		stored, err = GetTOTP(p)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.Skew)
+		code, err := TOTPCode(p)
+		require.NoError(t, err)
+		assert.True(t, TOTP{Secret: totp.Secret, Skew: 1}.Validate(code, time.Now()))
+
+		require.NoError(t, DeleteTOTP(p))
+		assert.False(t, p.Has(TOTPKey))
+	})
+
+	t.Run("Manager stores the seed next to the identity", func(t *testing.T) {
+		m := NewSecureManager("label", Memory)
+		require.NoError(t, m.SetAll(Identity{User: "john", Secret: "password"}))
+		require.NoError(t, m.SetTOTP(totp))
+
+		code, err := m.TOTPCode()
+		require.NoError(t, err)
+		assert.Len(t, code, 6)
+		stored, err := m.GetTOTP()
+		require.NoError(t, err)
+		assert.Equal(t, "john", stored.Account)
+
+		require.NoError(t, m.DeleteTOTP())
+		identity, err := m.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "john", identity.User)
+	})
+
+	t.Run("Record field", func(t *testing.T) {
+		type login struct {
+			User string `cred:"username"`
+			TOTP *TOTP  `cred:"totp,optional,omitempty"`
+		}
+		r, err := NewRecord[login](NewMemoryProvider("label"))
+		require.NoError(t, err)
+		require.NoError(t, r.Set(login{User: "john", TOTP: &totp}))
+		value, err := r.Get()
+		require.NoError(t, err)
+		require.NotNil(t, value.TOTP)
+		assert.Equal(t, totp.Secret, value.TOTP.Secret)
+	})
+
+	t.Run("Corrupted seed", func(t *testing.T) {
+		p := NewMemoryProvider("label")
+		require.NoError(t, p.Set(TOTPKey, "otpauth://totp/john?secret=!"))
+		_, err := TOTPCode(p)
+		assert.ErrorIs(t, err, ErrInvalidTOTP)
+	})
+}
@@ -0,0 +1,314 @@
+package cred
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/r3dpixel/toolkit/bytex"
+	"github.com/r3dpixel/toolkit/filex"
+	"github.com/r3dpixel/toolkit/sonicx"
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	ErrSecretNotFound  = errors.New("secret not found")
+	ErrVaultKeyMissing = errors.New("vault passphrase or key file not configured")
+	ErrVaultCorrupted  = errors.New("vault is corrupted or the key is wrong")
+	ErrInvalidKDF      = errors.New("invalid key derivation parameters")
+)
+
+const (
+	vaultMagic      = "TKVAULT" // Magic bytes at the start of a vault file
+	vaultVersion    = 1         // Version of the vault file format
+	vaultKDFArgon2  = 1         // Identifier of the argon2id key derivation
+	vaultSaltSize   = 16        // Size of the key derivation salt
+	vaultKeySize    = 32        // Size of the AES-256 key
+	vaultPermission = 0600      // Permissions of the vault and lock files
+	vaultFileName   = "credentials.vault"
+	vaultDirName    = "toolkit"
+	vaultMaxSize    = 16 * bytex.MiB // Largest vault file accepted
+
+	defaultKDFTime    = 3
+	defaultKDFMemory  = 64 * bytex.MiB
+	defaultKDFThreads = 4
+	maxKDFTime        = 100
+	maxKDFMemory      = 1 * bytex.GiB
+)
+
+// KDFParams are the argon2id key derivation parameters
+type KDFParams struct {
+	Time    uint32     // Number of passes (default 3)
+	Memory  bytex.Size // Memory used by the derivation (default 64MiB)
+	Threads uint8      // Degree of parallelism (default 4)
+}
+
+// valid returns true if the parameters can be used for a derivation (bounded, so a tampered header cannot exhaust memory)
+func (p KDFParams) valid() bool {
+	return p.Time >= 1 && p.Time <= maxKDFTime &&
+		p.Threads >= 1 &&
+		p.Memory >= 8*bytex.Size(p.Threads)*bytex.KiB && p.Memory <= maxKDFMemory
+}
+
+// VaultOptions configures the encrypted vault file of the File mode
+type VaultOptions struct {
+	Path       string    // Path of the vault file (default <user config dir>/toolkit/credentials.vault)
+	Passphrase string    // Passphrase the encryption key is derived from
+	KeyFile    string    // File whose contents the key is derived from (text is trimmed, takes precedence over Passphrase)
+	KDF        KDFParams // Key derivation parameters used when creating a vault (existing vaults keep theirs)
+}
+
+// vaultEntries maps from credential label to key-value pairs
+type vaultEntries map[string]map[string]string
+
+// vaultHeader is the plain text header of a vault file (authenticated as additional data)
+type vaultHeader struct {
+	kdf  KDFParams
+	salt []byte
+}
+
+// vaultKey is a derived key, cached for the salt and parameters it was derived with
+type vaultKey struct {
+	kdf  KDFParams
+	salt []byte
+	key  []byte
+}
+
+// vault is an encrypted file storing the entries of any number of labels.
+// Every access takes a lock file (shared for reads, exclusive for updates), so processes can share the vault.
+type vault struct {
+	mu     sync.Mutex
+	opts   VaultOptions
+	cached *vaultKey
+}
+
+// newVault creates a new vault with the given options
+func newVault(opts VaultOptions) *vault {
+	// Set default values if needed
+	if opts.Path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		opts.Path = filepath.Join(dir, vaultDirName, vaultFileName)
+	}
+	if opts.KDF.Time == 0 {
+		opts.KDF.Time = defaultKDFTime
+	}
+	if opts.KDF.Memory <= 0 {
+		opts.KDF.Memory = defaultKDFMemory
+	}
+	if opts.KDF.Threads == 0 {
+		opts.KDF.Threads = defaultKDFThreads
+	}
+
+	// Create the vault
+	return &vault{opts: opts}
+}
+
+// read calls fn with the entries of the vault, under a shared lock
+func (v *vault) read(fn func(entries vaultEntries)) error {
+	// Lock the vault within the process
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// Lock the vault across processes
+	lock, err := filex.RLock(v.opts.Path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// Load the entries
+	entries, _, err := v.load()
+	if err != nil {
+		return err
+	}
+	fn(entries)
+	return nil
+}
+
+// update calls fn with the entries of the vault under an exclusive lock, saving them if fn returns true
+func (v *vault) update(fn func(entries vaultEntries) bool) error {
+	// Lock the vault within the process
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// Lock the vault across processes
+	lock, err := filex.Lock(v.opts.Path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// Load the entries
+	entries, header, err := v.load()
+	if err != nil {
+		return err
+	}
+
+	// Apply the changes and save the entries if needed
+	if !fn(entries) {
+		return nil
+	}
+	return v.save(entries, header)
+}
+
+// load reads and decrypts the vault file (a missing file is an empty vault with a nil header)
+func (v *vault) load() (vaultEntries, *vaultHeader, error) {
+	// Read the file
+	data, err := os.ReadFile(v.opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return vaultEntries{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Parse the header
+	r := bytex.NewBinaryReader(bytes.NewReader(data), bytex.BinaryOptions{Limit: vaultMaxSize})
+	magic := r.Bytes(len(vaultMagic))
+	version := r.U8()
+	kdfID := r.U8()
+	header := &vaultHeader{kdf: KDFParams{Time: r.U32(), Memory: bytex.Size(r.U32()) * bytex.KiB, Threads: r.U8()}}
+	header.salt = r.PrefixedBytes(bytex.PrefixU8)
+	nonce := r.PrefixedBytes(bytex.PrefixU8)
+	switch {
+	case r.Err() != nil:
+		return nil, nil, fmt.Errorf("%w: %w", ErrVaultCorrupted, r.Err())
+	case string(magic) != vaultMagic:
+		return nil, nil, fmt.Errorf("%w: not a vault file", ErrVaultCorrupted)
+	case version != vaultVersion || kdfID != vaultKDFArgon2:
+		return nil, nil, fmt.Errorf("%w: unsupported version %d (key derivation %d)", ErrVaultCorrupted, version, kdfID)
+	case !header.kdf.valid():
+		return nil, nil, fmt.Errorf("%w: %w", ErrVaultCorrupted, ErrInvalidKDF)
+	}
+
+	// Decrypt the entries (the header is authenticated)
+	aead, err := v.aead(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerSize := int(r.Pos())
+	if len(nonce) != aead.NonceSize() {
+		return nil, nil, fmt.Errorf("%w: invalid nonce", ErrVaultCorrupted)
+	}
+	plaintext, err := aead.Open(nil, nonce, data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, nil, ErrVaultCorrupted
+	}
+	defer clear(plaintext)
+
+	// Decode the entries
+	entries := vaultEntries{}
+	if err := sonicx.Config.Unmarshal(plaintext, &entries); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrVaultCorrupted, err)
+	}
+	return entries, header, nil
+}
+
+// save encrypts and writes the vault file atomically (a nil header creates a new salt)
+func (v *vault) save(entries vaultEntries, header *vaultHeader) error {
+	// Create the header of a new vault (with parameters load accepts, so a bad option cannot lock every label out)
+	if header == nil {
+		if !v.opts.KDF.valid() {
+			return fmt.Errorf("%w: time %d, memory %s, threads %d", ErrInvalidKDF, v.opts.KDF.Time, v.opts.KDF.Memory, v.opts.KDF.Threads)
+		}
+		header = &vaultHeader{kdf: v.opts.KDF, salt: make([]byte, vaultSaltSize)}
+		if _, err := rand.Read(header.salt); err != nil {
+			return err
+		}
+	}
+
+	// Create the cipher and a fresh nonce
+	aead, err := v.aead(header)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	// Write the header
+	var buf bytes.Buffer
+	w := bytex.NewBinaryWriter(&buf)
+	w.Bytes([]byte(vaultMagic))
+	w.U8(vaultVersion)
+	w.U8(vaultKDFArgon2)
+	w.U32(header.kdf.Time)
+	w.U32(uint32(header.kdf.Memory / bytex.KiB))
+	w.U8(header.kdf.Threads)
+	w.PrefixedBytes(bytex.PrefixU8, header.salt)
+	w.PrefixedBytes(bytex.PrefixU8, nonce)
+	if err := w.Err(); err != nil {
+		return err
+	}
+
+	// Encode and encrypt the entries after the header
+	plaintext, err := sonicx.Config.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	defer clear(plaintext)
+	data := aead.Seal(buf.Bytes(), nonce, plaintext, buf.Bytes())
+
+	// Write the file atomically, readable only by the owner
+	return filex.WriteFileAtomic(v.opts.Path, data, vaultPermission)
+}
+
+// aead returns the AES-256-GCM cipher for the header, deriving the key if not cached
+func (v *vault) aead(header *vaultHeader) (cipher.AEAD, error) {
+	// Derive the key if the cached one does not match the header
+	if v.cached == nil || v.cached.kdf != header.kdf || subtle.ConstantTimeCompare(v.cached.salt, header.salt) != 1 {
+		secret, err := v.secret()
+		if err != nil {
+			return nil, err
+		}
+		key := argon2.IDKey(secret, header.salt, header.kdf.Time, uint32(header.kdf.Memory/bytex.KiB), header.kdf.Threads, vaultKeySize)
+		clear(secret)
+		v.cached = &vaultKey{kdf: header.kdf, salt: header.salt, key: key}
+	}
+
+	// Create the cipher
+	block, err := aes.NewCipher(v.cached.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyFileSecret returns the contents of a key file, trimming the surrounding whitespace of text files only
+// (binary keys are used as is, even if they start or end with whitespace bytes)
+func keyFileSecret(data []byte) []byte {
+	trimmed := bytes.TrimSpace(data)
+	if !utf8.Valid(trimmed) || bytes.ContainsFunc(trimmed, unicode.IsControl) {
+		return data
+	}
+	return trimmed
+}
+
+// secret returns the material the key is derived from (the key file contents or the passphrase)
+func (v *vault) secret() ([]byte, error) {
+	switch {
+	case v.opts.KeyFile != "":
+		data, err := os.ReadFile(v.opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return keyFileSecret(data), nil
+	case v.opts.Passphrase != "":
+		return []byte(v.opts.Passphrase), nil
+	default:
+		return nil, ErrVaultKeyMissing
+	}
+}
@@ -1,6 +1,7 @@
 package filex
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -16,8 +17,18 @@ import (
 const (
 	DirectoryPermission = 0700 // Default directory permissions given on creation
 	FilePermission      = 0644 // Default file permissions given on creation
+
+	copyBufferSize = 32 * bytex.KiB // Buffer size used by CopyBuffered
 )
 
+// CopyOptions configures CopyBuffered and CopyFile
+type CopyOptions struct {
+	Context    context.Context    // Cancels pending waits on the limiter
+	Limiter    *bytex.Limiter     // Limits the copy throughput (may be shared with other copies and downloads)
+	OnProgress bytex.ProgressFunc // Reports the progress of the copy (periodically and once at the end)
+	Total      bytex.Size         // Expected size for the progress reports (CopyFile uses the source file size)
+}
+
 type Entry byte
 
 const (
@@ -85,17 +96,46 @@ func GetCWD() string {
 	return ""
 }
 
-// CopyBuffered copies the input io.Reader to the given output io.Writer using a buffer of 32KB
-func CopyBuffered(r io.Reader, w io.Writer) error {
-	buf := bytex.Buffer32k.Get().([]byte)
-	defer bytex.Buffer32k.Put(buf)
+// CopyBuffered copies the input io.Reader to the given output io.Writer using a buffer of 32KB,
+// optionally throttled by a limiter and reporting the progress
+func CopyBuffered(r io.Reader, w io.Writer, opts ...CopyOptions) error {
+	// Resolve the options
+	var opt CopyOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Context == nil {
+		opt.Context = context.Background()
+	}
+
+	// Throttle the reader if requested
+	if opt.Limiter != nil {
+		r = opt.Limiter.Reader(opt.Context, r)
+	}
+
+	// Track the progress if requested
+	if opt.OnProgress != nil {
+		progress := bytex.NewProgressReader(r, bytex.ProgressOptions{Total: opt.Total, OnProgress: opt.OnProgress})
+		defer progress.Finish()
+		r = progress
+	}
+
+	// Copy using a pooled buffer
+	buf := bytex.Buffers.Get(int(copyBufferSize))
+	defer bytex.Buffers.Put(buf)
 
 	_, err := io.CopyBuffer(w, r, buf)
 	return err
 }
 
-// CopyFile copies the src file to the dst, using a buffered read/write
-func CopyFile(src, dst string) error {
+// CopyFile copies the src file to the dst, using a buffered read/write (see CopyBuffered for the options)
+func CopyFile(src, dst string, opts ...CopyOptions) error {
+	// Resolve the options
+	var opt CopyOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
 	// Open the source file
 	srcFile, err := os.Open(src)
 	if err != nil {
@@ -103,6 +143,13 @@ func CopyFile(src, dst string) error {
 	}
 	defer srcFile.Close()
 
+	// Use the source file size as the expected size of the progress reports
+	if opt.OnProgress != nil && opt.Total <= 0 {
+		if stat, err := srcFile.Stat(); err == nil {
+			opt.Total = bytex.Size(stat.Size())
+		}
+	}
+
 	// Create the destination file
 	dstFile, err := os.Create(dst)
 	if err != nil {
@@ -111,7 +158,54 @@ func CopyFile(src, dst string) error {
 	defer dstFile.Close()
 
 	// Copy the contents from the source file to the destination file buffered
-	return CopyBuffered(srcFile, dstFile)
+	return CopyBuffered(srcFile, dstFile, opt)
+}
+
+// WriteFileAtomic writes the data to a temporary file next to the path, then renames it over the path,
+// so readers see either the old or the new contents, never a partial write
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	// Create the parent directory
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, DirectoryPermission); err != nil {
+		return err
+	}
+
+	// Create the temporary file in the same directory (renames are only atomic within a file system)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	// Write the data and flush it to disk
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Replace the file
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	// Flush the directory entry (best effort, not supported on every platform)
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
 }
 
 // NextAvailablePath returns the next available path for the given path, optionally with an extension
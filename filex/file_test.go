@@ -4,9 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"fmt"
 
+	"github.com/r3dpixel/toolkit/bytex"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -138,6 +140,38 @@ func TestCopyFile(t *testing.T) {
 		assert.Equal(t, content, string(dstContent))
 	})
 
+	t.Run("Throttled by limiter", func(t *testing.T) {
+		tempDir := t.TempDir()
+		srcPath := filepath.Join(tempDir, "source.bin")
+		dstPath := filepath.Join(tempDir, "dest.bin")
+		content := make([]byte, 20*bytex.KiB)
+		require.NoError(t, os.WriteFile(srcPath, content, FilePermission))
+
+		limiter := bytex.NewLimiter(bytex.PerSecond(100*bytex.KiB), bytex.LimiterOptions{Burst: 10 * bytex.KiB})
+		start := time.Now()
+		err := CopyFile(srcPath, dstPath, CopyOptions{Limiter: limiter})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+		dstContent, err := os.ReadFile(dstPath)
+		assert.NoError(t, err)
+		assert.Equal(t, content, dstContent)
+	})
+
+	t.Run("Reports progress", func(t *testing.T) {
+		tempDir := t.TempDir()
+		srcPath := filepath.Join(tempDir, "source.bin")
+		dstPath := filepath.Join(tempDir, "dest.bin")
+		require.NoError(t, os.WriteFile(srcPath, make([]byte, 100*bytex.KiB), FilePermission))
+
+		var last bytex.Progress
+		err := CopyFile(srcPath, dstPath, CopyOptions{OnProgress: func(p bytex.Progress) { last = p }})
+		assert.NoError(t, err)
+		assert.True(t, last.Done)
+		assert.Equal(t, 100*bytex.KiB, last.Total)
+		assert.Equal(t, 100*bytex.KiB, last.Transferred)
+	})
+
 	t.Run("Source does not exist", func(t *testing.T) {
 		err := CopyFile("non-existent-file.txt", "destination.txt")
 		assert.Error(t, err)
@@ -145,6 +179,38 @@ func TestCopyFile(t *testing.T) {
 	})
 }
 
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("Creates the file and parent directories", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "a", "b", "secret.txt")
+
+		require.NoError(t, WriteFileAtomic(path, []byte("first"), 0600))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", string(content))
+
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+	})
+
+	t.Run("Replaces the file without leftovers", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "data.txt")
+
+		require.NoError(t, WriteFileAtomic(path, []byte("first"), FilePermission))
+		require.NoError(t, WriteFileAtomic(path, []byte("second"), FilePermission))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", string(content))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
+
 func TestNextAvailablePath(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "next_available_*")
 	require.NoError(t, err)
@@ -0,0 +1,52 @@
+package filex
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileLock is an advisory lock on a file, shared between processes (and between goroutines of the same process)
+type FileLock struct {
+	file *os.File
+}
+
+// Lock acquires an exclusive lock on the file at the given path, creating it if needed (blocks until acquired)
+func Lock(path string) (*FileLock, error) {
+	return acquireLock(path, true)
+}
+
+// RLock acquires a shared lock on the file at the given path, creating it if needed (blocks until acquired)
+func RLock(path string) (*FileLock, error) {
+	return acquireLock(path, false)
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() error {
+	// Release the lock, then close the file
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// acquireLock opens the lock file and locks it
+func acquireLock(path string, exclusive bool) (*FileLock, error) {
+	// Create the parent directory
+	if err := os.MkdirAll(filepath.Dir(path), DirectoryPermission); err != nil {
+		return nil, err
+	}
+
+	// Open the lock file
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock the file
+	if err := lockFile(file, exclusive); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &FileLock{file: file}, nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix && !windows
+
+package filex
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile reports that file locking is not supported on this platform
+func lockFile(*os.File, bool) error {
+	return errors.ErrUnsupported
+}
+
+// unlockFile reports that file locking is not supported on this platform
+func unlockFile(*os.File) error {
+	return errors.ErrUnsupported
+}
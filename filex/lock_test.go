@@ -0,0 +1,82 @@
+package filex
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLock(t *testing.T) {
+	t.Run("Exclusive lock serializes holders", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "test.lock")
+		var holders, maxHolders atomic.Int32
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lock, err := Lock(path)
+				if !assert.NoError(t, err) {
+					return
+				}
+				if current := holders.Add(1); current > maxHolders.Load() {
+					maxHolders.Store(current)
+				}
+				time.Sleep(100 * time.Microsecond)
+				holders.Add(-1)
+				assert.NoError(t, lock.Unlock())
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), maxHolders.Load())
+		assert.True(t, FileExists(path))
+	})
+
+	t.Run("Shared locks do not block each other", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.lock")
+
+		first, err := RLock(path)
+		require.NoError(t, err)
+		second, err := RLock(path)
+		require.NoError(t, err)
+
+		assert.NoError(t, first.Unlock())
+		assert.NoError(t, second.Unlock())
+	})
+
+	t.Run("Exclusive lock waits for shared locks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.lock")
+
+		shared, err := RLock(path)
+		require.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			lock, err := Lock(path)
+			if assert.NoError(t, err) {
+				close(acquired)
+				_ = lock.Unlock()
+			}
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("exclusive lock acquired while a shared lock is held")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		assert.NoError(t, shared.Unlock())
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("exclusive lock not acquired after the shared lock was released")
+		}
+	})
+}
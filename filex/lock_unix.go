@@ -0,0 +1,29 @@
+//go:build unix
+
+package filex
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile locks the file with flock (exclusive or shared)
+func lockFile(file *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+
+	// Retry if interrupted by a signal
+	for {
+		err := syscall.Flock(int(file.Fd()), how)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile unlocks the file
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package filex
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange is the number of bytes locked (the whole file, as LockFileEx locks byte ranges)
+const lockRange = ^uint32(0)
+
+// lockFile locks the file with LockFileEx (exclusive or shared)
+func lockFile(file *os.File, exclusive bool) error {
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, lockRange, lockRange, new(windows.Overlapped))
+}
+
+// unlockFile unlocks the file
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, lockRange, lockRange, new(windows.Overlapped))
+}
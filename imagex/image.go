@@ -39,7 +39,7 @@ func ToBytes(imageSource image.Image, format imgconv.Format) ([]byte, error) {
 	// Calculate the size of the buffer
 	bounds := imageSource.Bounds()
 	size := bytex.Size(bounds.Dx()*bounds.Dy()*3) * bytex.B
-	// Create the buffer
+	// Create the buffer (not pooled: the encoded image is returned without copying, and may be large)
 	buf := bytes.NewBuffer(make([]byte, 0, size))
 	// Write the image to the buffer
 	if err := To(imageSource, buf, format); err != nil {
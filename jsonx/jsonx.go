@@ -20,6 +20,7 @@ const (
 	Extension             = ".json"
 	defaultBufferSizeIO   = 32 * bytex.KiB // 32KB buffer for file I/O operations
 	defaultBufferSizeJSON = bytex.KiB      // Initial capacity for in-memory JSON encoding
+	maxPooledSizeJSON     = 64 * bytex.KiB // Largest JSON output copied out of a pooled buffer
 )
 
 // Options for JSON encoding and decoding
@@ -241,16 +242,24 @@ func ToFile[T any](item T, path string, opts ...Options) error {
 
 // ToBytes encodes the item to JSON and returns it as a byte slice with optional formatting options
 func ToBytes[T any](item T, opts ...Options) ([]byte, error) {
-	// Create a buffer for the JSON data
-	buf := bytes.NewBuffer(make([]byte, 0, defaultBufferSizeJSON))
+	// Get a pooled buffer for the JSON data
+	buf := bytex.Buffers.GetBuffer(int(defaultBufferSizeJSON))
 
 	// Encode the item to JSON and write it to the buffer
 	if err := ToJSON(item, buf, opts...); err != nil {
+		bytex.Buffers.PutBuffer(buf)
 		return nil, err
 	}
 
-	// Return the buffer bytes
-	return buf.Bytes(), nil
+	// Hand large outputs off without copying (the buffer is not returned to the pool)
+	if buf.Len() > int(maxPooledSizeJSON) {
+		return buf.Bytes(), nil
+	}
+
+	// Return a copy of small outputs, and reuse the buffer
+	data := bytes.Clone(buf.Bytes())
+	bytex.Buffers.PutBuffer(buf)
+	return data, nil
 }
 
 // FromJSON decodes JSON from the input reader into type [T]
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -754,6 +755,17 @@ func TestToBytes(t *testing.T) {
 		assert.NoError(t, err)
 		assert.JSONEq(t, "[]", string(result))
 	})
+
+	t.Run("Large output is handed off", func(t *testing.T) {
+		input := TestStruct{Value: strings.Repeat("x", int(2*maxPooledSizeJSON)), Count: 1}
+		first, err := ToBytes(input)
+		assert.NoError(t, err)
+		second, err := ToBytes(TestStruct{Value: "small"})
+		assert.NoError(t, err)
+
+		assert.JSONEq(t, `{"value":"small","count":0}`, string(second))
+		assert.Equal(t, `{"value":"`+input.Value+`","count":1}`, strings.TrimSpace(string(first)))
+	})
 }
 
 func TestFromJSON(t *testing.T) {
@@ -3,10 +3,13 @@ package reqx
 import (
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/imroc/req/v3"
+	"github.com/r3dpixel/toolkit/bytex"
 	"github.com/r3dpixel/toolkit/cred"
 )
 
@@ -51,6 +54,7 @@ type Options struct {
 	AutoDecode        bool
 	DisableKeepAlives bool
 	Impersonation     Impersonation
+	DownloadLimiter   *bytex.Limiter // Limits the throughput of response bodies (may be shared with other clients and copies)
 }
 
 // Config is a function that configures the underlying req.Client (for advanced use cases)
@@ -294,10 +298,38 @@ func newRetryClient(opts Options) *req.Client {
 	default:
 	}
 
+	// Throttle the response bodies if requested
+	if opts.DownloadLimiter != nil {
+		client.GetTransport().WrapRoundTripFunc(limitResponseBody(opts.DownloadLimiter))
+	}
+
 	// Return the client
 	return client
 }
 
+// limitedBody is a response body limited by a bytex.Limiter
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// limitResponseBody returns a transport middleware that throttles the response bodies with the limiter
+func limitResponseBody(limiter *bytex.Limiter) req.HttpRoundTripWrapperFunc {
+	return func(rt http.RoundTripper) req.HttpRoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			// Perform the request
+			resp, err := rt.RoundTrip(r)
+			if err != nil || resp.Body == nil {
+				return resp, err
+			}
+
+			// Wrap the body (the request context cancels pending waits)
+			resp.Body = &limitedBody{Reader: limiter.Reader(r.Context(), resp.Body), Closer: resp.Body}
+			return resp, nil
+		}
+	}
+}
+
 // responseErrorCause returns the error cause for the given response (safely wraps the response)
 // Only possible cases:
 // - response == nil, err != nil
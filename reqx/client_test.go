@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/imroc/req/v3"
+	"github.com/r3dpixel/toolkit/bytex"
 	"github.com/r3dpixel/toolkit/cred"
 	"github.com/stretchr/testify/assert"
 )
@@ -436,3 +437,23 @@ func TestClient_RegisterAuth_MethodChaining(t *testing.T) {
 	assert.Contains(t, client.auths, "service-1")
 	assert.Contains(t, client.auths, "service-2")
 }
+
+func TestNewClient_DownloadLimiter(t *testing.T) {
+	payload := make([]byte, 20*bytex.KiB)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	limiter := bytex.NewLimiter(bytex.PerSecond(100*bytex.KiB), bytex.LimiterOptions{Burst: bytex.KiB})
+	client := NewClient(Options{DownloadLimiter: limiter})
+
+	start := time.Now()
+	body, err := Bytes(client.R().Get(server.URL))
+	assert.NoError(t, err)
+	assert.Len(t, body, len(payload))
+
+	// 19KiB over the initial burst at 100KiB/s
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
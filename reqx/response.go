@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/imroc/req/v3"
+	"github.com/r3dpixel/toolkit/bytex"
 	"github.com/r3dpixel/toolkit/stringsx"
 )
 
@@ -32,3 +33,36 @@ func Stream(resp *req.Response, err error) (io.ReadCloser, error) {
 	}
 	return resp.Body, nil
 }
+
+// StreamWithProgress returns a stream extractor reporting the progress of the body as it is read
+// use for chaining: StreamWithProgress(opts)(client.R().Get(url))
+// The expected size defaults to the response content length (when known)
+func StreamWithProgress(opts bytex.ProgressOptions) func(*req.Response, error) (io.ReadCloser, error) {
+	return func(resp *req.Response, err error) (io.ReadCloser, error) {
+		body, err := Stream(resp, err)
+		if err != nil {
+			return nil, err
+		}
+
+		// Use the content length as the expected size (on a copy, so the extractor can be reused)
+		respOpts := opts
+		if respOpts.Total <= 0 && resp.ContentLength > 0 {
+			respOpts.Total = bytex.Size(resp.ContentLength)
+		}
+
+		// Wrap the body
+		return &progressBody{ProgressReader: bytex.NewProgressReader(body, respOpts), body: body}, nil
+	}
+}
+
+// progressBody is a response body reporting the progress as it is read
+type progressBody struct {
+	*bytex.ProgressReader
+	body io.ReadCloser
+}
+
+// Close sends the final report (if the body was not read to the end) and closes the body
+func (b *progressBody) Close() error {
+	b.Finish()
+	return b.body.Close()
+}
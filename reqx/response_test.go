@@ -5,8 +5,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
+	"github.com/r3dpixel/toolkit/bytex"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -105,3 +107,71 @@ func TestStream(t *testing.T) {
 		assert.Nil(t, stream)
 	})
 }
+
+func TestStreamWithProgress(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "14")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("stream content"))
+		}))
+		defer server.Close()
+
+		var reports []bytex.Progress
+		client := NewClient(Options{})
+		stream, err := StreamWithProgress(bytex.ProgressOptions{
+			OnProgress: func(p bytex.Progress) { reports = append(reports, p) },
+		})(client.R().Get(server.URL))
+
+		require.NoError(t, err)
+		require.NotNil(t, stream)
+
+		content, err := io.ReadAll(stream)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("stream content"), content)
+		require.NoError(t, stream.Close())
+
+		require.Len(t, reports, 1)
+		assert.True(t, reports[0].Done)
+		assert.Equal(t, bytex.Size(14), reports[0].Transferred)
+		assert.Equal(t, bytex.Size(14), reports[0].Total)
+	})
+
+	t.Run("Reused extractor uses each content length", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := r.URL.Query().Get("body")
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		var reports []bytex.Progress
+		client := NewClient(Options{})
+		extract := StreamWithProgress(bytex.ProgressOptions{
+			OnProgress: func(p bytex.Progress) { reports = append(reports, p) },
+		})
+
+		for _, body := range []string{"0123456789", "01234567890123456789"} {
+			stream, err := extract(client.R().SetQueryParam("body", body).Get(server.URL))
+			require.NoError(t, err)
+			_, err = io.Copy(io.Discard, stream)
+			require.NoError(t, err)
+			require.NoError(t, stream.Close())
+		}
+
+		require.Len(t, reports, 2)
+		assert.Equal(t, bytex.Size(10), reports[0].Total)
+		assert.Equal(t, bytex.Size(20), reports[1].Transferred)
+		assert.Equal(t, bytex.Size(20), reports[1].Total)
+	})
+
+	t.Run("Err propagation", func(t *testing.T) {
+		client := NewClient(Options{})
+
+		stream, err := StreamWithProgress(bytex.ProgressOptions{})(client.R().Get("http://invalid-url-that-does-not-exist-12345.com"))
+
+		assert.Error(t, err)
+		assert.Nil(t, stream)
+	})
+}
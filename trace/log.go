@@ -22,6 +22,13 @@ const (
 	ORIGIN   string = "origin"
 	SIZE     string = "size"
 	NAME     string = "name"
+	STACK    string = "stack"
+	ATTEMPT  string = "attempt"
+	ELAPSED  string = "elapsed"
+	LABEL    string = "label"
+	KEY      string = "key"
+	COMMAND  string = "command"
+	STDERR   string = "stderr"
 )
 
 // ConsoleTraceWriter creates a zerolog console writer configured for trace output